@@ -0,0 +1,197 @@
+package anitogo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AnalysisOptions configures AnalyzeEpisodes.
+type AnalysisOptions struct {
+	// Number of episodes every series is expected to have. When set, episodes 1 to ExpectedCount
+	// are checked for gaps and higher numbers are reported as out of range. Zero to only check
+	// for gaps between the lowest and highest episode found.
+	ExpectedCount int
+}
+
+// EpisodeFiles is an episode number and the names of the files holding it.
+type EpisodeFiles struct {
+	// Episode number.
+	Episode int `json:"episode"`
+
+	// Names of the files holding the episode, in the order they were given.
+	Files []string `json:"files"`
+}
+
+// SeriesReport is the result of analysing the files of one season of a series.
+type SeriesReport struct {
+	// Title of the series as written in the first file of the group.
+	Title string `json:"title"`
+
+	// Season of the series. Zero if the files do not name one.
+	Season int `json:"season,omitempty"`
+
+	// Number of files in the group.
+	Files int `json:"files"`
+
+	// Regular episode numbers found, sorted and without duplicates. Ranges like "01-12" are expanded.
+	Episodes []int `json:"episodes"`
+
+	// Episode numbers missing from the regular episodes.
+	Missing []int `json:"missing,omitempty"`
+
+	// Episodes held by more than one file with the same release version.
+	Duplicates []EpisodeFiles `json:"duplicates,omitempty"`
+
+	// Episodes held by files with different release versions, e.g "07" and "07v2".
+	Versions []EpisodeFiles `json:"versions,omitempty"`
+
+	// Fractional and recap episodes, e.g "12.5", which are not counted as regular episodes.
+	Specials []string `json:"specials,omitempty"`
+
+	// Episode numbers outside of the expected range: zero, and numbers above AnalysisOptions.ExpectedCount.
+	OutOfRange []int `json:"out_of_range,omitempty"`
+
+	// Expected number of episodes, from AnalysisOptions.
+	ExpectedCount int `json:"expected_count,omitempty"`
+
+	// True if an expected count was given and every episode from 1 to it was found.
+	Complete bool `json:"complete"`
+}
+
+type seriesGroup struct {
+	report   *SeriesReport
+	versions map[int]map[int][]string
+}
+
+// AnalyzeEpisodes groups the elements by their normalised title and season and reports the episodes
+// that are missing, duplicated, present in several versions, specials or out of range in each group.
+// Titles are compared ignoring case, punctuation and spacing. Reports are sorted by title and season.
+func AnalyzeEpisodes(elements []*Elements, options AnalysisOptions) []SeriesReport {
+	groups := map[string]*seriesGroup{}
+	var keys []string
+
+	for _, e := range elements {
+		season := 0
+		if len(e.AnimeSeason) > 0 {
+			season, _ = strconv.Atoi(e.AnimeSeason[0])
+		}
+		key := normalizeTitle(e.AnimeTitle) + "\x00" + strconv.Itoa(season)
+		g, found := groups[key]
+		if !found {
+			g = &seriesGroup{
+				report:   &SeriesReport{Title: e.AnimeTitle, Season: season, Episodes: []int{}, ExpectedCount: options.ExpectedCount},
+				versions: map[int]map[int][]string{},
+			}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.report.Files++
+
+		episodes, specials := expandEpisodes(e)
+		g.report.Specials = append(g.report.Specials, specials...)
+		version := releaseVersion(e)
+		for _, ep := range episodes {
+			if g.versions[ep] == nil {
+				g.versions[ep] = map[int][]string{}
+			}
+			g.versions[ep][version] = append(g.versions[ep][version], e.FileName)
+		}
+	}
+
+	sort.Strings(keys)
+	reports := []SeriesReport{}
+	for _, key := range keys {
+		g := groups[key]
+		g.analyze(options)
+		reports = append(reports, *g.report)
+	}
+	return reports
+}
+
+func (g *seriesGroup) analyze(options AnalysisOptions) {
+	r := g.report
+	var numbers []int
+	for ep := range g.versions {
+		numbers = append(numbers, ep)
+	}
+	sort.Ints(numbers)
+
+	for _, ep := range numbers {
+		versions := g.versions[ep]
+		if ep == 0 || (options.ExpectedCount > 0 && ep > options.ExpectedCount) {
+			r.OutOfRange = append(r.OutOfRange, ep)
+			continue
+		}
+		r.Episodes = append(r.Episodes, ep)
+
+		var all []string
+		var sortedVersions []int
+		for v := range versions {
+			sortedVersions = append(sortedVersions, v)
+		}
+		sort.Ints(sortedVersions)
+		duplicate := false
+		for _, v := range sortedVersions {
+			all = append(all, versions[v]...)
+			duplicate = duplicate || len(versions[v]) > 1
+		}
+		if duplicate {
+			r.Duplicates = append(r.Duplicates, EpisodeFiles{Episode: ep, Files: all})
+		}
+		if len(versions) > 1 {
+			r.Versions = append(r.Versions, EpisodeFiles{Episode: ep, Files: all})
+		}
+	}
+
+	first, last := 1, options.ExpectedCount
+	if options.ExpectedCount == 0 && len(r.Episodes) > 0 {
+		first, last = r.Episodes[0], r.Episodes[len(r.Episodes)-1]
+	}
+	present := map[int]bool{}
+	for _, ep := range r.Episodes {
+		present[ep] = true
+	}
+	for ep := first; ep <= last; ep++ {
+		if !present[ep] {
+			r.Missing = append(r.Missing, ep)
+		}
+	}
+	r.Complete = options.ExpectedCount > 0 && len(r.Missing) == 0
+}
+
+// expandEpisodes returns the regular episode numbers of e, with ranges expanded, and its special episodes.
+// Recaps and fractional episodes, e.g "12.5", are specials.
+func expandEpisodes(e *Elements) ([]int, []string) {
+	if e.Batch != nil && e.Batch.EpisodeCount > 0 {
+		var episodes []int
+		for ep := e.Batch.FirstEpisode; ep <= e.Batch.LastEpisode; ep++ {
+			episodes = append(episodes, ep)
+		}
+		return episodes, nil
+	}
+
+	recap := false
+	for _, edition := range e.Edition {
+		recap = recap || strings.EqualFold(edition, "RECAP")
+	}
+
+	var episodes []int
+	var specials []string
+	for _, number := range e.EpisodeNumber {
+		n, err := strconv.Atoi(number)
+		if err != nil || recap {
+			specials = append(specials, number)
+			continue
+		}
+		episodes = append(episodes, n)
+	}
+	if len(episodes) == 2 && episodes[0] < episodes[1] && isEpisodeRange(e.FileName, e.EpisodeNumber[0], e.EpisodeNumber[1]) {
+		first, last := episodes[0], episodes[1]
+		episodes = nil
+		for ep := first; ep <= last; ep++ {
+			episodes = append(episodes, ep)
+		}
+	}
+	return episodes, specials
+}
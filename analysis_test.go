@@ -0,0 +1,74 @@
+package anitogo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalysisAnalyzeEpisodes(t *testing.T) {
+	var elements []*Elements
+	for _, name := range []string{
+		"[Group] Title - 01 [1080p].mkv",
+		"[Group] Title - 02 [1080p].mkv",
+		"[Group] Title - 02v2 [1080p].mkv",
+		"[Other] TITLE - 04 [720p].mkv",
+		"[Group] Title - 04 [1080p].mkv",
+		"[Group] Title - 05-07 [1080p].mkv",
+		"[Group] Title - 07.5 [1080p].mkv",
+		"[Group] Title - 08 [Recap][1080p].mkv",
+		"[Group] Title - 00 [1080p].mkv",
+		"[Group] Title - 14 [1080p].mkv",
+		"[Group] Title S2 - 01 [1080p].mkv",
+	} {
+		elements = append(elements, Parse(name, DefaultOptions))
+	}
+
+	reports := AnalyzeEpisodes(elements, AnalysisOptions{})
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(reports))
+	}
+	r := reports[0]
+	if r.Title != "Title" || r.Season != 0 || r.Files != 10 {
+		t.Errorf("expected 10 files of \"Title\", got %d of \"%s\" season %d", r.Files, r.Title, r.Season)
+	}
+	if !reflect.DeepEqual(r.Episodes, []int{1, 2, 4, 5, 6, 7, 14}) {
+		t.Errorf("expected episodes 1, 2, 4 to 7 and 14, got %v", r.Episodes)
+	}
+	if !reflect.DeepEqual(r.Missing, []int{3, 8, 9, 10, 11, 12, 13}) {
+		t.Errorf("expected 3 and 8 to 13 missing, got %v", r.Missing)
+	}
+	expectedVersions := []EpisodeFiles{{Episode: 2, Files: []string{"[Group] Title - 02 [1080p].mkv", "[Group] Title - 02v2 [1080p].mkv"}}}
+	if !reflect.DeepEqual(r.Versions, expectedVersions) {
+		t.Errorf("expected %v, got %v", expectedVersions, r.Versions)
+	}
+	expectedDuplicates := []EpisodeFiles{{Episode: 4, Files: []string{"[Other] TITLE - 04 [720p].mkv", "[Group] Title - 04 [1080p].mkv"}}}
+	if !reflect.DeepEqual(r.Duplicates, expectedDuplicates) {
+		t.Errorf("expected %v, got %v", expectedDuplicates, r.Duplicates)
+	}
+	if !reflect.DeepEqual(r.Specials, []string{"07.5", "08"}) {
+		t.Errorf("expected specials 07.5 and 08, got %v", r.Specials)
+	}
+	if !reflect.DeepEqual(r.OutOfRange, []int{0}) || r.Complete {
+		t.Errorf("expected episode 0 out of range and not complete, got %v and %t", r.OutOfRange, r.Complete)
+	}
+	if reports[1].Season != 2 || !reflect.DeepEqual(reports[1].Episodes, []int{1}) {
+		t.Errorf("expected episode 1 of season 2, got %v", reports[1])
+	}
+
+	reports = AnalyzeEpisodes(elements, AnalysisOptions{ExpectedCount: 12})
+	r = reports[0]
+	if !reflect.DeepEqual(r.OutOfRange, []int{0, 14}) {
+		t.Errorf("expected 0 and 14 out of range, got %v", r.OutOfRange)
+	}
+	if !reflect.DeepEqual(r.Missing, []int{3, 8, 9, 10, 11, 12}) {
+		t.Errorf("expected 3 and 8 to 12 missing, got %v", r.Missing)
+	}
+	if r := reports[1]; r.Complete || len(r.Missing) != 11 {
+		t.Errorf("expected 11 missing episodes in season 2, got %v", r.Missing)
+	}
+
+	reports = AnalyzeEpisodes([]*Elements{Parse("[Group] Title (01-03) [BD 1080p]", DefaultOptions)}, AnalysisOptions{ExpectedCount: 3})
+	if !reports[0].Complete || !reflect.DeepEqual(reports[0].Episodes, []int{1, 2, 3}) {
+		t.Errorf("expected a complete batch of 3 episodes, got %v", reports[0])
+	}
+}
@@ -23,6 +23,11 @@ var DefaultOptions = Options{
 //
 // Parsing behavior can be customized in the passed Options struct.
 func Parse(filename string, options Options) *Elements {
+	return parse(filename, options, false)
+}
+
+// parse parses a filename, or the name of a directory if directory is true.
+func parse(filename string, options Options, directory bool) *Elements {
 	if len(filename) == 0 {
 		return &Elements{}
 	}
@@ -54,6 +59,7 @@ func Parse(filename string, options Options) *Elements {
 	tkz.tokenize()
 
 	psr := newParser(&tkz)
+	psr.directory = directory
 	psr.parse()
 
 	return psr.tokenizer.elements
@@ -0,0 +1,336 @@
+package anitogo
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AudioTrack is an audio codec found in the filename along with the details attached to it.
+type AudioTrack struct {
+	// Canonical name of the codec, e.g "E-AC-3" for "EAC3", "DDP" and "DD+".
+	Codec string `json:"codec,omitempty"`
+
+	// Channel layout of the track, e.g "2.0", "5.1" or "7.1".
+	Channels string `json:"channels,omitempty"`
+
+	// Number of tracks using the codec, e.g 2 for "AACx2" and "2xAAC".
+	Count int `json:"count,omitempty"`
+
+	// True if the codec is lossless, e.g FLAC or TrueHD.
+	Lossless bool `json:"lossless,omitempty"`
+
+	// True if the track carries Dolby Atmos object audio.
+	Atmos bool `json:"atmos,omitempty"`
+
+	// Language of the track when it is written next to the codec, e.g "JPN FLAC 2.0".
+	Language *Language `json:"language,omitempty"`
+
+	// Strings the track was parsed from, as they appear in the filename.
+	Raw []string `json:"raw,omitempty"`
+}
+
+// Audio describes the audio tracks of the file, built from the audio terms found in the filename.
+type Audio struct {
+	// Audio tracks in the order they appear in the filename.
+	Tracks []AudioTrack `json:"tracks,omitempty"`
+
+	// Number of audio tracks in the file, from an explicit count like "[3 Audio]"
+	// or from the tracks listed in the filename when there is more than one.
+	TrackCount int `json:"track_count,omitempty"`
+
+	// True if the release is tagged as dual audio.
+	DualAudio bool `json:"dual_audio,omitempty"`
+
+	// True if the release is tagged as having more than two audio languages, e.g "Multi Audio".
+	MultiAudio bool `json:"multi_audio,omitempty"`
+}
+
+type audioCodecInfo struct {
+	name     string
+	lossless bool
+
+	// Ambiguous codecs are only recognised when a channel layout or track count is attached, e.g "DD5.1".
+	ambiguous bool
+}
+
+var audioCodecTable = map[string]audioCodecInfo{
+	"AAC": {name: "AAC"}, "HE-AAC": {name: "HE-AAC"}, "HEAAC": {name: "HE-AAC"},
+	"AC3": {name: "AC-3"}, "AC-3": {name: "AC-3"}, "DD": {name: "AC-3", ambiguous: true},
+	"EAC3": {name: "E-AC-3"}, "E-AC3": {name: "E-AC-3"}, "E-AC-3": {name: "E-AC-3"},
+	"DDP": {name: "E-AC-3"}, "DD+": {name: "E-AC-3"},
+	"DTS": {name: "DTS"}, "DTS-ES": {name: "DTS-ES"},
+	"DTS-HD": {name: "DTS-HD MA", lossless: true}, "DTS-HDMA": {name: "DTS-HD MA", lossless: true},
+	"DTSHD": {name: "DTS-HD MA", lossless: true}, "DTS-HD MA": {name: "DTS-HD MA", lossless: true},
+	"TRUEHD": {name: "TrueHD", lossless: true}, "DOLBY TRUEHD": {name: "TrueHD", lossless: true},
+	"FLAC": {name: "FLAC", lossless: true}, "ALAC": {name: "ALAC", lossless: true},
+	"PCM": {name: "PCM", lossless: true}, "LPCM": {name: "PCM", lossless: true},
+	"OPUS": {name: "Opus"}, "VORBIS": {name: "Vorbis"}, "OGG": {name: "Vorbis"},
+	"MP2": {name: "MP2"}, "MP3": {name: "MP3"},
+}
+
+var (
+	audioTermPattern        *regexp.Regexp
+	audioChannelsPattern    = regexp.MustCompile("^(\\d)(?:\\.(\\d))?(CH)?$")
+	audioDualAudioTerms     = []string{"DUAL AUDIO", "DUALAUDIO", "DUAL-AUDIO"}
+	audioMultiAudioTerms    = []string{"MULTI AUDIO", "MULTIAUDIO", "MULTI-AUDIO"}
+	audioTrackCountSuffixes = []string{"AUDIO", "AUDIOS"}
+)
+
+func init() {
+	var codecs []string
+	for codec := range audioCodecTable {
+		codecs = append(codecs, regexp.QuoteMeta(codec))
+	}
+	// Longer codecs first so "EAC3" is not read as "E" followed by "AC3".
+	sort.Slice(codecs, func(i, j int) bool {
+		if len(codecs[i]) != len(codecs[j]) {
+			return len(codecs[i]) > len(codecs[j])
+		}
+		return codecs[i] < codecs[j]
+	})
+	audioTermPattern = regexp.MustCompile("^(?:(\\d)X)?(" + strings.Join(codecs, "|") + ")(?:X(\\d))?[-.]?(?:(\\d)(?:\\.(\\d))?(?:CH)?)?$")
+}
+
+type audioTerm struct {
+	codec    audioCodecInfo
+	channels string
+	count    int
+}
+
+// parseAudioTerm parses a single token into its codec, channel layout and track count, e.g "DDP5.1" or "2xAAC".
+func parseAudioTerm(str string) (audioTerm, bool) {
+	normalized := strings.ToUpper(strings.TrimSpace(str))
+	if match := audioTermPattern.FindStringSubmatch(normalized); match != nil {
+		term := audioTerm{codec: audioCodecTable[match[2]]}
+		if match[1] != "" {
+			term.count, _ = strconv.Atoi(match[1])
+		} else if match[3] != "" {
+			term.count, _ = strconv.Atoi(match[3])
+		}
+		if match[4] != "" {
+			term.channels = formatAudioChannels(match[4], match[5])
+		}
+		return term, true
+	}
+	if match := audioChannelsPattern.FindStringSubmatch(normalized); match != nil {
+		if match[2] == "" && match[3] == "" {
+			return audioTerm{}, false
+		}
+		return audioTerm{channels: formatAudioChannels(match[1], match[2])}, true
+	}
+	return audioTerm{}, false
+}
+
+func formatAudioChannels(main, lfe string) string {
+	if lfe == "" {
+		// "6CH" and "8CH" count the LFE channel.
+		switch main {
+		case "6":
+			return "5.1"
+		case "8":
+			return "7.1"
+		}
+		lfe = "0"
+	}
+	return main + "." + lfe
+}
+
+func (t audioTerm) valid() bool {
+	return t.codec.name != "" && (!t.codec.ambiguous || t.channels != "" || t.count != 0)
+}
+
+// searchForAudioTerms identifies the audio terms that are not in the keyword list,
+// e.g "DDP5.1", "2xAAC", a channel layout written after its codec or a track count like "[3 Audio]".
+func (p *parser) searchForAudioTerms() {
+	tkns := *p.tokenizer.tokens
+	for i, tkn := range tkns {
+		if tkn.Category != tokenCategoryUnknown {
+			continue
+		}
+		next, _ := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+
+		if isNumeric(tkn.Content) && next.Category == tokenCategoryUnknown {
+			if checkInList(audioTrackCountSuffixes, strings.ToUpper(next.Content)) {
+				tkn.Category = tokenCategoryIdentifier
+				next.Category = tokenCategoryIdentifier
+			}
+			continue
+		}
+
+		term, found := parseAudioTerm(tkn.Content)
+		if !found {
+			if strings.ToUpper(tkn.Content) == "DD" && i+1 < len(tkns) && tkns[i+1].Content == "+" {
+				p.tokenizer.elements.insert(elementCategoryAudioTerm, tkn.Content+"+")
+				tkn.Category = tokenCategoryIdentifier
+				tkns[i+1].Category = tokenCategoryIdentifier
+			}
+			continue
+		}
+		if term.codec.name == "" {
+			previous, _ := p.tokenizer.tokens.findPrevious(*tkn, tokenFlagsNotDelimiter)
+			previousTerm, found := parseAudioTerm(previous.Content)
+			if !found || previous.Category != tokenCategoryIdentifier || previousTerm.codec.name == "" {
+				continue
+			}
+		} else if !term.valid() {
+			continue
+		} else if term.channels == "" && term.count == 0 && !tkn.Enclosed && !p.isNextToAudioTerm(tkn) {
+			// A bare codec name can be a title word, e.g "Opus" in "Opus.COLORs".
+			continue
+		}
+		kd, found := p.tokenizer.keywordManager.findWithoutCategory(p.tokenizer.keywordManager.normalize(tkn.Content))
+		if !found || kd.category != elementCategoryAudioTerm || !kd.options.searchable {
+			p.tokenizer.elements.insert(elementCategoryAudioTerm, tkn.Content)
+		}
+		tkn.Category = tokenCategoryIdentifier
+	}
+}
+
+// isNextToAudioTerm returns true if the token before or after tkn is an identified audio term,
+// or if the token after it is a channel layout, e.g "PCM 2.0".
+func (p *parser) isNextToAudioTerm(tkn *token) bool {
+	previous, _ := p.tokenizer.tokens.findPrevious(*tkn, tokenFlagsNotDelimiter)
+	next, _ := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+	if term, found := parseAudioTerm(next.Content); found && term.codec.name == "" {
+		return true
+	}
+	for _, neighbour := range []*token{previous, next} {
+		if neighbour.Category != tokenCategoryIdentifier {
+			continue
+		}
+		if _, found := parseAudioTerm(neighbour.Content); found || checkInList(p.tokenizer.elements.AudioTerm, neighbour.Content) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) buildAudio() {
+	e := p.tokenizer.elements
+	a := Audio{}
+	found := false
+	pendingChannels := ""
+
+	for i, tkn := range *p.tokenizer.tokens {
+		if tkn.Category == tokenCategoryDelimiter || tkn.Category == tokenCategoryBracket {
+			continue
+		}
+		if tkn.Category != tokenCategoryIdentifier && !checkInList(e.AudioTerm, tkn.Content) {
+			continue
+		}
+		normalized := p.tokenizer.keywordManager.normalize(tkn.Content)
+
+		switch {
+		case checkInList(audioDualAudioTerms, normalized):
+			a.DualAudio = true
+			found = true
+			continue
+		case checkInList(audioMultiAudioTerms, normalized):
+			a.MultiAudio = true
+			found = true
+			continue
+		case normalized == "ATMOS" || normalized == "DOLBY ATMOS":
+			if len(a.Tracks) > 0 {
+				a.Tracks[len(a.Tracks)-1].Atmos = true
+			} else {
+				a.Tracks = append(a.Tracks, AudioTrack{Atmos: true})
+			}
+			a.Tracks[len(a.Tracks)-1].Raw = append(a.Tracks[len(a.Tracks)-1].Raw, tkn.Content)
+			found = true
+			continue
+		case normalized == "LOSSLESS":
+			if len(a.Tracks) > 0 {
+				a.Tracks[len(a.Tracks)-1].Lossless = true
+			}
+			found = true
+			continue
+		case isNumeric(tkn.Content):
+			next, _ := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+			if checkInList(audioTrackCountSuffixes, strings.ToUpper(next.Content)) {
+				a.TrackCount, _ = strconv.Atoi(tkn.Content)
+				found = true
+			}
+			continue
+		}
+
+		raw := tkn.Content
+		if normalized == "DD+" {
+			normalized = "DDP"
+		}
+		term, ok := parseAudioTerm(normalized)
+		if !ok || (term.codec.name == "" && term.channels == "") {
+			continue
+		}
+		found = true
+
+		if term.codec.name == "" {
+			previous, _ := p.tokenizer.tokens.findPrevious(*tkn, tokenFlagsNotDelimiter)
+			previousTerm, _ := parseAudioTerm(previous.Content)
+			last := len(a.Tracks) - 1
+			if last >= 0 && previousTerm.codec.name != "" && a.Tracks[last].Channels == "" {
+				a.Tracks[last].Channels = term.channels
+				a.Tracks[last].Raw = append(a.Tracks[last].Raw, raw)
+			} else {
+				pendingChannels = term.channels
+			}
+			continue
+		}
+
+		track := AudioTrack{
+			Codec:    term.codec.name,
+			Channels: term.channels,
+			Count:    term.count,
+			Lossless: term.codec.lossless,
+			Raw:      []string{raw},
+		}
+		if track.Channels == "" && pendingChannels != "" {
+			track.Channels = pendingChannels
+		}
+		pendingChannels = ""
+		if lang, found := p.findAudioLanguage(i); found {
+			track.Language = &lang
+			e.insertLanguage(lang)
+		}
+		a.Tracks = append(a.Tracks, track)
+	}
+
+	if a.TrackCount == 0 && len(a.Tracks) > 0 {
+		for _, t := range a.Tracks {
+			if t.Count > 1 {
+				a.TrackCount += t.Count
+			} else {
+				a.TrackCount++
+			}
+		}
+		if a.TrackCount < 2 {
+			a.TrackCount = 0
+		}
+	}
+
+	if found {
+		e.Audio = &a
+	}
+}
+
+// findAudioLanguage returns the language written right before an audio codec, e.g "JPN" in "JPN FLAC 2.0".
+func (p *parser) findAudioLanguage(index int) (Language, bool) {
+	tkns := *p.tokenizer.tokens
+	if index < 2 || tkns[index-1].Category != tokenCategoryDelimiter {
+		return Language{}, false
+	}
+	if strings.Contains(languageListSeparators, tkns[index-1].Content) {
+		return Language{}, false
+	}
+	m, found := matchLanguageToken(tkns[index-2])
+	if !found || len(m.languages) != 1 || m.track == LanguageTrackSubtitles {
+		return Language{}, false
+	}
+	lang := m.languages[0]
+	if lang.Track == LanguageTrackSubtitles {
+		return Language{}, false
+	}
+	lang.Track = LanguageTrackAudio
+	return lang, true
+}
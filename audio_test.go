@@ -0,0 +1,111 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestAudioParseAudioTerm(t *testing.T) {
+	cases := map[string]audioTerm{
+		"AAC2.0":    {codec: audioCodecTable["AAC"], channels: "2.0"},
+		"DDP5.1":    {codec: audioCodecTable["DDP"], channels: "5.1"},
+		"E-AC-3":    {codec: audioCodecTable["E-AC-3"]},
+		"2xAAC":     {codec: audioCodecTable["AAC"], count: 2},
+		"FLACx3":    {codec: audioCodecTable["FLAC"], count: 3},
+		"DD2":       {codec: audioCodecTable["DD"], channels: "2.0"},
+		"5.1ch":     {channels: "5.1"},
+		"6CH":       {channels: "5.1"},
+		"TrueHD7.1": {codec: audioCodecTable["TRUEHD"], channels: "7.1"},
+	}
+	for str, expected := range cases {
+		term, found := parseAudioTerm(str)
+		if !found {
+			t.Errorf("expected \"%s\" to be an audio term", str)
+			continue
+		}
+		if term != expected {
+			t.Errorf("expected %v, got %v for \"%s\"", expected, term, str)
+		}
+	}
+	for _, str := range []string{"5", "H264", "AC"} {
+		if _, found := parseAudioTerm(str); found {
+			t.Errorf("expected \"%s\" not to be an audio term", str)
+		}
+	}
+	term, _ := parseAudioTerm("DD")
+	if term.valid() {
+		t.Error("expected false, got true")
+	}
+}
+
+func TestAudioBuildAudio(t *testing.T) {
+	e := Parse("[Group] Title - 01 [1080p][JPN FLAC 2.0][ENG AAC2.0].mkv", DefaultOptions)
+	if e.Audio == nil || len(e.Audio.Tracks) != 2 {
+		t.Fatalf("expected 2 audio tracks, got %v", e.Audio)
+	}
+	flac := e.Audio.Tracks[0]
+	if flac.Codec != "FLAC" || flac.Channels != "2.0" || !flac.Lossless {
+		t.Errorf("expected lossless FLAC 2.0, got %v", flac)
+	}
+	if flac.Language == nil || flac.Language.ISO6393 != "jpn" {
+		t.Errorf("expected Japanese, got %v", flac.Language)
+	}
+	aac := e.Audio.Tracks[1]
+	if aac.Codec != "AAC" || aac.Channels != "2.0" || aac.Language == nil || aac.Language.ISO6393 != "eng" {
+		t.Errorf("expected English AAC 2.0, got %v", aac)
+	}
+	if e.Audio.TrackCount != 2 {
+		t.Errorf("expected 2, got %d", e.Audio.TrackCount)
+	}
+
+	e = Parse("[Group] Title - 01 [WEB 1080p DDP5.1 Atmos][3 Audio].mkv", DefaultOptions)
+	if e.Audio == nil || len(e.Audio.Tracks) != 1 {
+		t.Fatalf("expected 1 audio track, got %v", e.Audio)
+	}
+	if e.Audio.Tracks[0].Codec != "E-AC-3" || e.Audio.Tracks[0].Channels != "5.1" || !e.Audio.Tracks[0].Atmos {
+		t.Errorf("expected E-AC-3 5.1 with Atmos, got %v", e.Audio.Tracks[0])
+	}
+	if e.Audio.TrackCount != 3 {
+		t.Errorf("expected 3, got %d", e.Audio.TrackCount)
+	}
+	if e.EpisodeNumber[0] != "01" {
+		t.Errorf("expected \"01\", got \"%s\"", e.EpisodeNumber[0])
+	}
+
+	e = Parse("[Group] Title - 01 (BD 1080p E-AC-3 5.1 2xAAC Dual Audio).mkv", DefaultOptions)
+	if e.Audio == nil || !e.Audio.DualAudio || e.Audio.TrackCount != 3 {
+		t.Errorf("expected dual audio with 3 tracks, got %v", e.Audio)
+	}
+
+	e = Parse("[Group] Title - 01 [Opus 5.1][DD+ 2.0].mkv", DefaultOptions)
+	if e.Audio == nil || len(e.Audio.Tracks) != 2 {
+		t.Fatalf("expected 2 audio tracks, got %v", e.Audio)
+	}
+	if e.Audio.Tracks[0].Codec != "Opus" || e.Audio.Tracks[1].Codec != "E-AC-3" || e.Audio.Tracks[1].Channels != "2.0" {
+		t.Errorf("expected Opus and E-AC-3 2.0, got %v", e.Audio.Tracks)
+	}
+
+	e = Parse("[Group] Title - 01 [1080p].mkv", DefaultOptions)
+	if e.Audio != nil {
+		t.Errorf("expected nil, got %v", e.Audio)
+	}
+}
+
+func TestAudioSearchForAudioTerms(t *testing.T) {
+	cases := map[string]string{
+		"[Group] Opus.COLORs - 01 [1080p].mkv":    "Opus COLORs",
+		"[Group] Opus - 01 [1080p].mkv":           "Opus",
+		"[Group] PCM Girls - 01 [1080p].mkv":      "PCM Girls",
+		"[Group] Title - 01 [1080p Opus 5.1].mkv": "Title",
+	}
+	for str, expected := range cases {
+		e := Parse(str, DefaultOptions)
+		if e.AnimeTitle != expected {
+			t.Errorf("expected \"%s\", got \"%s\" for \"%s\"", expected, e.AnimeTitle, str)
+		}
+	}
+
+	e := Parse("[Group] Title - 01 [1080p] PCM 2.0.mkv", DefaultOptions)
+	if !checkInList(e.AudioTerm, "PCM") {
+		t.Errorf("expected \"PCM\" in %v", e.AudioTerm)
+	}
+}
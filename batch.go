@@ -0,0 +1,124 @@
+package anitogo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Batch describes the episodes and seasons covered by a batch or season pack.
+type Batch struct {
+	// First episode of the covered range. Zero when the pack only names its seasons, e.g "Title S01 Complete".
+	FirstEpisode int `json:"first_episode,omitempty"`
+
+	// Last episode of the covered range.
+	LastEpisode int `json:"last_episode,omitempty"`
+
+	// Number of episodes in the covered range.
+	EpisodeCount int `json:"episode_count,omitempty"`
+
+	// Seasons covered by the pack, e.g []int{1, 2, 3} for "S01-S03".
+	Seasons []int `json:"seasons,omitempty"`
+
+	// True if the pack is tagged as complete or as a batch, e.g "COMPLETE" or "BATCH".
+	Complete bool `json:"complete,omitempty"`
+}
+
+var batchKeywords = []string{"BATCH", "COMPLETE"}
+
+// batchMinimumUntaggedRange is the number of episodes an untagged range must cover to be a batch.
+// Shorter ranges like "316-317" are usually a single file holding two episodes.
+const batchMinimumUntaggedRange = 3
+
+// buildBatch decides whether the name is a batch or season pack. Names with a video extension are single files.
+// Other names are packs when they are tagged "BATCH" or "COMPLETE", have an episode range in brackets, e.g "(01-24)",
+// have an untagged range of several episodes, e.g "1-13", or name a season without an episode, e.g "Title S2".
+func (p *parser) buildBatch() {
+	e := p.tokenizer.elements
+	if e.FileExtension != "" && IsVideoExtension(e.FileExtension) {
+		return
+	}
+
+	b := Batch{}
+	for _, info := range e.ReleaseInformation {
+		if checkInList(batchKeywords, p.tokenizer.keywordManager.normalize(info)) {
+			b.Complete = true
+		}
+	}
+	b.Seasons = expandSeasons(e.AnimeSeason)
+
+	first, last, enclosed, isRange := p.findEpisodeRange()
+	switch {
+	case isRange && (b.Complete || enclosed || last-first+1 >= batchMinimumUntaggedRange):
+		b.FirstEpisode, b.LastEpisode = first, last
+		b.EpisodeCount = last - first + 1
+	case len(e.EpisodeNumber) == 0 && (b.Complete || len(b.Seasons) > 0):
+	default:
+		return
+	}
+	e.Batch = &b
+}
+
+// findEpisodeRange returns the episode range of the name, e.g 1 and 24 for "01-24" or "01 ~ 24",
+// and whether the range is enclosed in brackets. Lists like "01+02" are not ranges.
+func (p *parser) findEpisodeRange() (int, int, bool, bool) {
+	e := p.tokenizer.elements
+	if len(e.EpisodeNumber) != 2 {
+		return 0, 0, false, false
+	}
+	first, err1 := strconv.Atoi(e.EpisodeNumber[0])
+	last, err2 := strconv.Atoi(e.EpisodeNumber[1])
+	if err1 != nil || err2 != nil || first >= last {
+		return 0, 0, false, false
+	}
+
+	if !isEpisodeRange(e.FileName, e.EpisodeNumber[0], e.EpisodeNumber[1]) {
+		return 0, 0, false, false
+	}
+
+	enclosed := false
+	for _, tkn := range *p.tokenizer.tokens {
+		if tkn.Category == tokenCategoryIdentifier && strings.HasPrefix(tkn.Content, e.EpisodeNumber[0]) {
+			enclosed = tkn.Enclosed
+			break
+		}
+	}
+	return first, last, enclosed, true
+}
+
+// expandSeasons returns the seasons covered by the AnimeSeason values, where two values are a range, e.g "S01-S03".
+func expandSeasons(values []string) []int {
+	var seasons []int
+	for _, v := range values {
+		if n, err := strconv.Atoi(v); err == nil {
+			seasons = append(seasons, n)
+		}
+	}
+	if len(seasons) == 2 && seasons[0] < seasons[1] {
+		first, last := seasons[0], seasons[1]
+		seasons = nil
+		for n := first; n <= last; n++ {
+			seasons = append(seasons, n)
+		}
+	}
+	return seasons
+}
+
+// batchEpisodeRangePattern matches two episode numbers written as a range, e.g "01-24", "01v2 ~ 24" or "E01-E24".
+var batchEpisodeRangePattern = regexp.MustCompile(`(?i)(?:^|\D)(\d+(?:\.\d+)?)(?:v\d)?\s*[-~]\s*(?:EP?)?(\d+(?:\.\d+)?)(?:v\d)?(?:\D|$)`)
+
+// isEpisodeRange returns true if the two episode numbers are written as a range in name, e.g "01-24" or "01 ~ 24".
+func isEpisodeRange(name, first, last string) bool {
+	for i := 0; i < len(name); {
+		m := batchEpisodeRangePattern.FindStringSubmatchIndex(name[i:])
+		if m == nil {
+			return false
+		}
+		if name[i+m[2]:i+m[3]] == first && name[i+m[4]:i+m[5]] == last {
+			return true
+		}
+		// Ranges can overlap, e.g "01-02-03", so the search goes on after the first number.
+		i += m[3]
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package anitogo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchBuildBatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected *Batch
+	}{
+		{"[Group] Title (01-24) [BD 1080p]", &Batch{FirstEpisode: 1, LastEpisode: 24, EpisodeCount: 24}},
+		{"[Group] Title - 01-12 [Batch]", &Batch{FirstEpisode: 1, LastEpisode: 12, EpisodeCount: 12, Complete: true}},
+		{"[Erai-raws] Great Pretender - 01 ~ 14 [720p][Multiple Subtitle]", &Batch{FirstEpisode: 1, LastEpisode: 14, EpisodeCount: 14}},
+		{"Title S01 Complete", &Batch{Seasons: []int{1}, Complete: true}},
+		{"[Group] Title Season 2 [1080p]", &Batch{Seasons: []int{2}}},
+		{"[HorribleSubs] Boku no Hero Academia S01-S03E01-E75 [1080p]", &Batch{FirstEpisode: 1, LastEpisode: 75, EpisodeCount: 75, Seasons: []int{1, 2, 3}}},
+		{"Detective Conan - 316-317 [DCTP][2411959B]", nil},
+		{"[HorribleSubs] Momokuri - 01+02 [720p]", nil},
+		{"[HorribleSubs] Tsukimonogatari - (01-04) [1080p].mkv", nil},
+		{"[SubDESU-H] Swing out Sisters Complete Version (720p x264 8bit AC3) [3ABD57E6].mp4", nil},
+		{"[SubsPlease] Title - 05 (1080p)", nil},
+	}
+
+	for _, tc := range testCases {
+		e := Parse(tc.name, DefaultOptions)
+		if !reflect.DeepEqual(e.Batch, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, e.Batch)
+		}
+	}
+
+	e := Parse("[Group] Title (01-24) [BD 1080p]", DefaultOptions)
+	if !reflect.DeepEqual(e.EpisodeNumber, []string{"01", "24"}) {
+		t.Errorf("expected the episode numbers to be kept, got %v", e.EpisodeNumber)
+	}
+}
+
+func TestBatchIsEpisodeRange(t *testing.T) {
+	cases := []struct {
+		name, first, last string
+		expected          bool
+	}{
+		{"[Group] Title (01-24) [1080p]", "01", "24", true},
+		{"[Group] Title 01v2 ~ 24 [1080p]", "01", "24", true},
+		{"Title S01E01-E12 1080p", "01", "12", true},
+		{"[Group] Title 01-02-03 [1080p]", "02", "03", true},
+		{"[Group] Title 101-124 [1080p]", "01", "24", false},
+		{"[Group] Title - 01 [1080p][24]", "01", "24", false},
+	}
+	for _, c := range cases {
+		if got := isEpisodeRange(c.name, c.first, c.last); got != c.expected {
+			t.Errorf("expected %t for %q, got %t", c.expected, c.name, got)
+		}
+	}
+}
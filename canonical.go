@@ -0,0 +1,204 @@
+package anitogo
+
+func addCanonicalValues(kwm *keywordManager) {
+	kwm.setCanonical(elementCategoryAnimeSeasonPrefix, "Season", []string{"S", "SAISON", "SEASON"})
+	kwm.setCanonical(elementCategoryAnimeType, "Movie", []string{"GEKIJOUBAN", "MOVIE"})
+	kwm.setCanonical(elementCategoryAnimeType, "OVA", []string{"OAD", "OAV", "OVA"})
+	kwm.setCanonical(elementCategoryAnimeType, "ONA", []string{"ONA"})
+	kwm.setCanonical(elementCategoryAnimeType, "Special", []string{"SP", "SPECIAL", "SPECIALS"})
+	kwm.setCanonical(elementCategoryAnimeType, "TV", []string{"TV"})
+	kwm.setCanonical(elementCategoryAnimeType, "ED", []string{"ED", "ENDING"})
+	kwm.setCanonical(elementCategoryAnimeType, "OP", []string{"OP", "OPENING"})
+	kwm.setCanonical(elementCategoryAnimeType, "NCED", []string{"NCED"})
+	kwm.setCanonical(elementCategoryAnimeType, "NCOP", []string{"NCOP"})
+	kwm.setCanonical(elementCategoryAnimeType, "Preview", []string{"PREVIEW"})
+	kwm.setCanonical(elementCategoryAnimeType, "PV", []string{"PV"})
+
+	kwm.setCanonical(elementCategoryAudioTerm, "2.0", []string{"2.0CH", "2CH"})
+	kwm.setCanonical(elementCategoryAudioTerm, "5.1", []string{"5.1", "5.1CH"})
+	kwm.setCanonical(elementCategoryAudioTerm, "7.1", []string{"7.1", "7.1CH"})
+	kwm.setCanonical(elementCategoryAudioTerm, "DTS", []string{"DTS", "DTS5.1"})
+	kwm.setCanonical(elementCategoryAudioTerm, "DTS-ES", []string{"DTS-ES"})
+	kwm.setCanonical(elementCategoryAudioTerm, "TrueHD", []string{"DOLBY TRUEHD", "TRUEHD", "TRUEHD5.1"})
+	kwm.setCanonical(elementCategoryAudioTerm, "AAC", []string{"AAC", "AACX2", "AACX3", "AACX4"})
+	kwm.setCanonical(elementCategoryAudioTerm, "AC-3", []string{"AC3", "DD2", "DD2.0"})
+	kwm.setCanonical(elementCategoryAudioTerm, "E-AC-3", []string{"EAC3", "E-AC-3"})
+	kwm.setCanonical(elementCategoryAudioTerm, "FLAC", []string{"FLAC", "FLACX2", "FLACX3", "FLACX4"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Lossless", []string{"LOSSLESS"})
+	kwm.setCanonical(elementCategoryAudioTerm, "MP3", []string{"MP3"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Opus", []string{"OPUS"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Vorbis", []string{"OGG", "VORBIS"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Atmos", []string{"ATMOS", "DOLBY ATMOS"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Dual Audio", []string{"DUALAUDIO", "DUAL AUDIO", "DUAL-AUDIO"})
+	kwm.setCanonical(elementCategoryAudioTerm, "Multi Audio", []string{"MULTIAUDIO", "MULTI AUDIO", "MULTI-AUDIO"})
+
+	kwm.setCanonical(elementCategoryEpisodePrefix, "Episode", []string{
+		"EP", "EP.", "EPS", "EPS.", "EPISODE", "EPISODE.", "EPISODES",
+		"CAPITULO", "EPISODIO", "EPISóDIO", "FOLGE", "E", "\x7B2C"})
+
+	kwm.setCanonical(elementCategoryLanguage, "en", []string{"ENG", "ENGLISH"})
+	kwm.setCanonical(elementCategoryLanguage, "es", []string{"ESP", "ESPANOL", "SPANISH"})
+	kwm.setCanonical(elementCategoryLanguage, "fr", []string{"VOSTFR"})
+	kwm.setCanonical(elementCategoryLanguage, "it", []string{"ITA"})
+	kwm.setCanonical(elementCategoryLanguage, "ja", []string{"JAP"})
+	kwm.setCanonical(elementCategoryLanguage, "pt-BR", []string{"PT-BR"})
+
+	kwm.setCanonical(elementCategoryEdition, "BD Edit", []string{"BD EDIT", "BD VERSION"})
+	kwm.setCanonical(elementCategoryEdition, "Director's Cut", []string{"DIRECTOR'S CUT", "DIRECTORS CUT"})
+	kwm.setCanonical(elementCategoryEdition, "Extended", []string{
+		"EXTENDED", "EXTENDED CUT", "EXTENDED EDITION", "EXTENDED VERSION"})
+	kwm.setCanonical(elementCategoryEdition, "Recap", []string{"RECAP"})
+	kwm.setCanonical(elementCategoryEdition, "Special Edition", []string{"SPECIAL EDITION"})
+	kwm.setCanonical(elementCategoryEdition, "Theatrical", []string{
+		"THEATRICAL", "THEATRICAL CUT", "THEATRICAL EDITION", "THEATRICAL VERSION"})
+	kwm.setCanonical(elementCategoryEdition, "TV Edit", []string{"TV CUT", "TV EDIT", "TV VERSION"})
+
+	kwm.setCanonical(elementCategoryOther, "Censored", []string{"CENSORED"})
+	kwm.setCanonical(elementCategoryOther, "Remastered", []string{"REMASTER", "REMASTERED"})
+	kwm.setCanonical(elementCategoryOther, "Uncensored", []string{"DECENSORED", "UNCENSORED"})
+	kwm.setCanonical(elementCategoryOther, "Uncut", []string{"UNCUT"})
+	kwm.setCanonical(elementCategoryOther, "Widescreen", []string{"WIDESCREEN", "WS"})
+
+	kwm.setCanonical(elementCategoryReleaseInformation, "Batch", []string{"BATCH"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Complete", []string{"COMPLETE"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "End", []string{"END"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Final", []string{"FINAL"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Patch", []string{"PATCH"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Remux", []string{"REMUX"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "DirFix", []string{"DIRFIX"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Internal", []string{"INTERNAL"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "NFOFix", []string{"NFOFIX"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Nuked", []string{"NUKED"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Proper", []string{"PROPER"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "ReadNFO", []string{"READNFO"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Repack", []string{"REPACK"})
+	kwm.setCanonical(elementCategoryReleaseInformation, "Rerip", []string{"RERIP"})
+
+	kwm.setCanonical(elementCategorySource, "Blu-ray", []string{"BD", "BDRIP", "BLURAY", "BLU-RAY"})
+	kwm.setCanonical(elementCategorySource, "DVD", []string{
+		"DVD", "DVD5", "DVD9", "DVD-R2J", "DVDRIP", "DVD-RIP", "R2DVD", "R2J", "R2JDVD", "R2JDVDRIP"})
+	kwm.setCanonical(elementCategorySource, "TV", []string{"HDTV", "HDTVRIP", "TVRIP", "TV-RIP"})
+	kwm.setCanonical(elementCategorySource, "WEB-DL", []string{"WEB", "WEB-DL", "WEBDL"})
+	kwm.setCanonical(elementCategorySource, "WEBRip", []string{"WEBCAST", "WEBRIP", "WEB-RIP"})
+	kwm.setCanonical(elementCategorySource, "LaserDisc", []string{"LASERDISC", "LDRIP"})
+	kwm.setCanonical(elementCategorySource, "VHS", []string{"VHS", "VHSRIP"})
+
+	kwm.setCanonical(elementCategoryStreamingService, "ABEMA", []string{"ABEMA"})
+	kwm.setCanonical(elementCategoryStreamingService, "Amazon Prime Video", []string{"AMZN"})
+	kwm.setCanonical(elementCategoryStreamingService, "Animation Digital Network", []string{"ADN"})
+	kwm.setCanonical(elementCategoryStreamingService, "AnimeLab", []string{"ANIMELAB"})
+	kwm.setCanonical(elementCategoryStreamingService, "Apple TV+", []string{"ATVP"})
+	kwm.setCanonical(elementCategoryStreamingService, "Bahamut Anime Crazy", []string{"BAHA"})
+	kwm.setCanonical(elementCategoryStreamingService, "Bilibili", []string{"B-GLOBAL", "BGLOBAL", "BILIBILI"})
+	kwm.setCanonical(elementCategoryStreamingService, "Crunchyroll", []string{"CR", "CRUNCHYROLL"})
+	kwm.setCanonical(elementCategoryStreamingService, "Disney+", []string{"DSNP"})
+	kwm.setCanonical(elementCategoryStreamingService, "Funimation", []string{"FUNI", "FUNIMATION"})
+	kwm.setCanonical(elementCategoryStreamingService, "HBO Max", []string{"HMAX"})
+	kwm.setCanonical(elementCategoryStreamingService, "HIDIVE", []string{"HIDIVE"})
+	kwm.setCanonical(elementCategoryStreamingService, "Hulu", []string{"HULU"})
+	kwm.setCanonical(elementCategoryStreamingService, "iQIYI", []string{"IQIYI"})
+	kwm.setCanonical(elementCategoryStreamingService, "Netflix", []string{"NF", "NETFLIX"})
+	kwm.setCanonical(elementCategoryStreamingService, "U-NEXT", []string{"U-NEXT"})
+	kwm.setCanonical(elementCategoryStreamingService, "VRV", []string{"VRV"})
+	kwm.setCanonical(elementCategoryStreamingService, "Wakanim", []string{"WAKANIM", "WKN"})
+
+	kwm.setCanonical(elementCategorySubtitles, "ASS", []string{"ASS"})
+	kwm.setCanonical(elementCategorySubtitles, "Big5", []string{"BIG5"})
+	kwm.setCanonical(elementCategorySubtitles, "Dub", []string{"DUB", "DUBBED"})
+	kwm.setCanonical(elementCategorySubtitles, "Hardsub", []string{"HARDSUB", "HARDSUBS"})
+	kwm.setCanonical(elementCategorySubtitles, "Raw", []string{"RAW"})
+	kwm.setCanonical(elementCategorySubtitles, "Softsub", []string{"SOFTSUB", "SOFTSUBS"})
+	kwm.setCanonical(elementCategorySubtitles, "Sub", []string{"SUB", "SUBBED", "SUBTITLED"})
+	kwm.setCanonical(elementCategorySubtitles, "Multi Sub", []string{
+		"MULTISUB", "MULTISUBS", "MULTI-SUB", "MULTI-SUBS", "MULTI SUB"})
+
+	kwm.setCanonical(elementCategoryVideoTerm, "8-bit", []string{"8BIT", "8-BIT"})
+	kwm.setCanonical(elementCategoryVideoTerm, "10-bit", []string{"10BIT", "10BITS", "10-BIT", "10-BITS"})
+	kwm.setCanonical(elementCategoryVideoTerm, "12-bit", []string{"12BIT", "12BITS", "12-BIT", "12-BITS"})
+	kwm.setCanonical(elementCategoryVideoTerm, "Hi10P", []string{"HI10", "HI10P"})
+	kwm.setCanonical(elementCategoryVideoTerm, "Hi444PP", []string{"HI444", "HI444P", "HI444PP"})
+	kwm.setCanonical(elementCategoryVideoTerm, "HDR", []string{"HDR"})
+	kwm.setCanonical(elementCategoryVideoTerm, "HDR10", []string{"HDR10"})
+	kwm.setCanonical(elementCategoryVideoTerm, "HDR10+", []string{"HDR10+", "HDR10PLUS"})
+	kwm.setCanonical(elementCategoryVideoTerm, "HLG", []string{"HLG"})
+	kwm.setCanonical(elementCategoryVideoTerm, "Dolby Vision", []string{"DV", "DOVI", "DOLBY VISION"})
+	kwm.setCanonical(elementCategoryVideoTerm, "H.264", []string{"H264", "H.264", "X264", "X.264", "AVC"})
+	kwm.setCanonical(elementCategoryVideoTerm, "H.265", []string{"H265", "H.265", "X265", "X.265", "HEVC", "HEVC2"})
+	kwm.setCanonical(elementCategoryVideoTerm, "AV1", []string{"AV1", "SVT-AV1"})
+	kwm.setCanonical(elementCategoryVideoTerm, "VC-1", []string{"VC-1", "VC1"})
+	kwm.setCanonical(elementCategoryVideoTerm, "VP9", []string{"VP9"})
+	kwm.setCanonical(elementCategoryVideoTerm, "MPEG-2", []string{"MPEG2", "MPEG-2"})
+	kwm.setCanonical(elementCategoryVideoTerm, "DivX", []string{"DIVX", "DIVX5", "DIVX6"})
+	kwm.setCanonical(elementCategoryVideoTerm, "XviD", []string{"XVID"})
+	kwm.setCanonical(elementCategoryVideoTerm, "WMV", []string{"WMV", "WMV3", "WMV9"})
+	kwm.setCanonical(elementCategoryVideoTerm, "2160p", []string{"4K", "UHD"})
+	kwm.setCanonical(elementCategoryVideoTerm, "4320p", []string{"8K"})
+	kwm.setCanonical(elementCategoryVideoTerm, "1440p", []string{"QHD"})
+	kwm.setCanonical(elementCategoryVideoTerm, "1080p", []string{"FHD"})
+
+	kwm.setCanonical(elementCategoryVolumePrefix, "Volume", []string{"VOL", "VOL.", "VOLUME"})
+}
+
+// buildCanonical fills Elements.Canonical with the canonical value of every keyword that was found.
+// Keywords without a canonical value fall back to their normalized form, and other values are kept as they are.
+func (p *parser) buildCanonical() {
+	e := p.tokenizer.elements
+	canonical := map[string][]string{}
+
+	for cat, name := range elementCategoryNames {
+		if !cat.isSearchable() || cat == elementCategoryReleaseGroup || cat == elementCategoryFileChecksum {
+			continue
+		}
+		if !e.contains(cat) {
+			continue
+		}
+
+		var values []string
+		for _, raw := range e.get(cat) {
+			value := p.canonicalValue(cat, raw)
+			if !checkInList(values, value) {
+				values = append(values, value)
+			}
+		}
+		canonical[name] = values
+	}
+
+	if len(canonical) > 0 {
+		e.Canonical = canonical
+	}
+}
+
+// canonicalValue returns the canonical value of a single element value. Terms matched by patterns rather than
+// keywords take the canonical value of what they describe, e.g "E-AC-3" for "DDP5.1" and "Repack" for "REPACK2".
+func (p *parser) canonicalValue(cat elementCategory, raw string) string {
+	e := p.tokenizer.elements
+	kwm := p.tokenizer.keywordManager
+	normalized := kwm.normalize(raw)
+	if cat == elementCategoryVideoResolution && e.Resolution != nil {
+		return e.Resolution.Label
+	}
+	if edition, found := editionOtherTable[normalized]; found && cat == elementCategoryEdition {
+		return edition
+	}
+	if kd, found := kwm.find(normalized, cat); found {
+		if kd.canonical == "" {
+			return normalized
+		}
+		return kd.canonical
+	}
+	switch cat {
+	case elementCategoryAudioTerm:
+		if term, found := parseAudioTerm(raw); found && term.codec.name != "" {
+			return term.codec.name
+		} else if found && term.channels != "" {
+			return term.channels
+		}
+	case elementCategoryReleaseInformation:
+		if match := revisionPattern.FindStringSubmatch(normalized); match != nil {
+			if kd, found := kwm.find(match[1], cat); found && kd.canonical != "" {
+				return kd.canonical
+			}
+		}
+	}
+	return raw
+}
@@ -0,0 +1,77 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestCanonicalSetCanonical(t *testing.T) {
+	kwm := &keywordManager{
+		keywords:       make(map[string]keyword),
+		fileExtensions: make(map[string]keyword),
+	}
+	kwm.add(elementCategorySource, keywordOptionsDefault, []string{"BD", "BLURAY"})
+	kwm.add(elementCategoryVideoTerm, keywordOptionsDefault, []string{"H264"})
+	kwm.setCanonical(elementCategorySource, "Blu-ray", []string{"BD", "BLURAY", "H264", "MISSING"})
+	if kwm.keywords["BD"].canonical != "Blu-ray" || kwm.keywords["BLURAY"].canonical != "Blu-ray" {
+		t.Error("expected canonical value \"Blu-ray\"")
+	}
+	if kwm.keywords["H264"].canonical != "" {
+		t.Errorf("expected empty canonical value, got \"%s\"", kwm.keywords["H264"].canonical)
+	}
+	if _, found := kwm.keywords["MISSING"]; found {
+		t.Error("expected false, got true")
+	}
+}
+
+func TestCanonicalBuildCanonical(t *testing.T) {
+	e := Parse("[TaigaSubs]_Toradora!_(2008)_-_01v2_-_Tiger_and_Dragon_[BD 1920x1080_x264_AVC_FLAC][1234ABCD].mkv", DefaultOptions)
+	expected := map[string][]string{
+		"audio_term":       {"FLAC"},
+		"release_version":  {"2"},
+		"source":           {"Blu-ray"},
+		"video_resolution": {"1080p"},
+		"video_term":       {"H.264"},
+	}
+	if len(e.Canonical) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, e.Canonical)
+	}
+	for k, v := range expected {
+		if !equal(e.Canonical[k], v) {
+			t.Errorf("expected %v, got %v for \"%s\"", v, e.Canonical[k], k)
+		}
+	}
+	if !equal(e.VideoTerm, []string{"x264", "AVC"}) {
+		t.Errorf("expected raw values to be kept, got %v", e.VideoTerm)
+	}
+
+	e = Parse("Byousoku 5 Centimeter [Blu-Ray][1920x1080 H.264][2.0ch AAC][SOFTSUBS]", DefaultOptions)
+	if !equal(e.Canonical["audio_term"], []string{"2.0", "AAC"}) {
+		t.Errorf("expected [2.0 AAC], got %v", e.Canonical["audio_term"])
+	}
+	if !equal(e.Canonical["subtitles"], []string{"Softsub"}) {
+		t.Errorf("expected [Softsub], got %v", e.Canonical["subtitles"])
+	}
+
+	cases := map[string]map[string][]string{
+		"[Group] Title - 01 [WEB 1080p DDP5.1].mkv":           {"audio_term": {"E-AC-3"}},
+		"[Group] Title - 01 [1080p AAC2.0].mkv":               {"audio_term": {"AAC"}},
+		"[Group] Title - 01 (BD 1080p 2xAAC).mkv":             {"audio_term": {"AAC"}},
+		"[Group] Title - 01 [1080p][DD+ 2.0].mkv":             {"audio_term": {"E-AC-3", "2.0"}},
+		"Title.S01E02.REPACK2.1080p.WEB-DL.H.264.mkv":         {"release_information": {"Repack"}, "source": {"WEB-DL"}},
+		"[Group] Title - 01 [1080p WEBRip].mkv":               {"source": {"WEBRip"}},
+		"[Group] Title - 01 [1080p FLAC 5.1ch][ABCD1234].mkv": {"audio_term": {"FLAC", "5.1"}},
+	}
+	for filename, fields := range cases {
+		e = Parse(filename, DefaultOptions)
+		for k, v := range fields {
+			if !equal(e.Canonical[k], v) {
+				t.Errorf("expected %v, got %v for \"%s\" in \"%s\"", v, e.Canonical[k], k, filename)
+			}
+		}
+	}
+
+	e = Parse("Toradora", DefaultOptions)
+	if e.Canonical != nil {
+		t.Errorf("expected nil, got %v", e.Canonical)
+	}
+}
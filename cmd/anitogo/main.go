@@ -0,0 +1,79 @@
+// Command anitogo parses anime filenames from the command line.
+//
+// Usage:
+//
+//	anitogo <command> [flags] [arguments]
+//
+// Run "anitogo <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of anitogo. run returns the exit code of the command.
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"organise": {summary: "move, copy or link video files into a folder layout", run: runOrganise},
+	"scan":     {summary: "walk a directory tree and catalogue every video file", run: runScan},
+	"serve":    {summary: "serve the parser over HTTP", run: runServe},
+	"verify":   {summary: "check video files against the CRC32 checksum in their name", run: runVerify},
+	"watch":    {summary: "poll a directory and run actions on new video files", run: runWatch},
+}
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return exitUsage
+	}
+	cmd, found := commands[args[0]]
+	if !found {
+		fmt.Fprintf(stderr, "anitogo: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: anitogo <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+}
+
+// stringsFlag is a flag that can be repeated, e.g "-include '*.mkv' -include '*.mp4'".
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/nssteinbrenner/anitogo"
+	"github.com/nssteinbrenner/anitogo/mediaserver"
+	"github.com/nssteinbrenner/anitogo/organise"
+)
+
+func runOrganise(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("organise", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	template := flags.String("template", organise.DefaultTemplate, "naming template of the target paths")
+	profileName := flags.String("profile", "", "name files for a media server instead of using -template: plex, plex-absolute, jellyfin, jellyfin-absolute or kodi")
+	nfo := flags.Bool("nfo", false, "write Kodi NFO sidecars next to the organised files, always done for -profile kodi")
+	mode := flags.String("mode", string(organise.ModeMove), "\"move\", \"copy\", \"hardlink\" or \"symlink\"")
+	dryRun := flags.Bool("dry-run", false, "print the operations without performing them")
+	journal := flags.String("journal", "", "append the operations performed to this file so they can be undone")
+	undo := flags.String("undo", "", "undo the operations recorded in this journal instead of organising")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anitogo organise [flags] <dir> <root>")
+		fmt.Fprintln(stderr, "       anitogo organise -undo <journal>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Puts every video file under <dir> at the path rendered from the template under <root>.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *undo != "" {
+		if flags.NArg() != 0 {
+			flags.Usage()
+			return exitUsage
+		}
+		if err := organise.Undo(*undo); err != nil {
+			fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	var profile *mediaserver.Profile
+	var t *organise.Template
+	var err error
+	if *profileName != "" {
+		if profile, err = mediaserver.LookupProfile(*profileName); err == nil {
+			t = profile.Template
+		}
+	} else {
+		t, err = organise.ParseTemplate(*template)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+		return exitUsage
+	}
+	// Without -profile, the Kodi profile only numbers the NFO sidecars and leaves the paths alone.
+	var nfoProfile *mediaserver.Profile
+	if *nfo || (profile != nil && profile.NFO) {
+		nfoProfile = profile
+		if nfoProfile == nil {
+			nfoProfile = mediaserver.Profiles["kodi"]
+		}
+	}
+	m, err := organise.ParseMode(*mode)
+	if err != nil {
+		fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+		return exitUsage
+	}
+
+	s := &scanner{root: flags.Arg(0), options: anitogo.DefaultOptions, visited: map[string]bool{}}
+	if err := s.scan(); err != nil {
+		fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+		return exitError
+	}
+	items := make([]organise.Item, 0, len(s.records))
+	elements := map[string]*anitogo.Elements{}
+	for _, r := range s.records {
+		e := r.Elements
+		if profile != nil {
+			if e, err = profile.Elements(e); err != nil {
+				fmt.Fprintf(stderr, "skipped %s: %v\n", r.Path, err)
+				continue
+			}
+		}
+		// Operations hold absolute paths.
+		abs, err := filepath.Abs(r.Path)
+		if err != nil {
+			abs = r.Path
+		}
+		elements[abs] = e
+		items = append(items, organise.Item{Path: r.Path, Elements: e})
+	}
+
+	o := &organise.Organiser{Root: flags.Arg(1), Template: t, Mode: m}
+	plan := o.Plan(items)
+	for _, skipped := range plan.Skipped {
+		fmt.Fprintf(stderr, "skipped %s: %s\n", skipped.Source, skipped.Reason)
+	}
+	for _, c := range plan.Conflicts {
+		fmt.Fprintf(stderr, "conflict at %s: %s\n", c.Target, c.Reason)
+		for _, source := range c.Sources {
+			fmt.Fprintf(stderr, "  %s\n", source)
+		}
+	}
+
+	err = organise.Execute(plan, organise.ExecuteOptions{DryRun: *dryRun, Journal: *journal, Output: stdout})
+	if err != nil {
+		fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+		return exitError
+	}
+	if nfoProfile != nil && !*dryRun {
+		for _, op := range plan.Operations {
+			if err := writeSidecars(nfoProfile, op.Target, elements[op.Source], *journal, stdout); err != nil {
+				s.errors = append(s.errors, err)
+			}
+		}
+	}
+	for _, err := range s.errors {
+		fmt.Fprintf(stderr, "anitogo organise: %v\n", err)
+	}
+	if len(plan.Conflicts) > 0 || len(s.errors) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// writeSidecars writes the NFO sidecars of an organised video and records them in the journal so undo removes them.
+func writeSidecars(profile *mediaserver.Profile, video string, e *anitogo.Elements, journal string, output io.Writer) error {
+	sidecars, err := profile.Sidecars(video, e)
+	if err != nil {
+		return err
+	}
+	for _, sidecar := range sidecars {
+		if err := organise.CreateFile(sidecar.Path, sidecar.Data, organise.ExecuteOptions{Journal: journal, Output: output}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
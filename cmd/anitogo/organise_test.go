@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOrganiseRunOrganise(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"[Group] Title (BD 1080p)/[Group] Title - 01 (BD 1080p) [ABCD1234].mkv": "01",
+		"[Group] Title (BD 1080p)/[Group] Title - 02 (BD 1080p) [ABCD1235].mkv": "02",
+		"notes.txt": "",
+	})
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+	target := filepath.Join(root, "Title", "Season 01", "Title - S01E02.mkv")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"organise", "-dry-run", src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if lines := strings.Count(stdout.String(), "would move "); lines != 2 {
+		t.Errorf("expected 2 planned moves, got %q", stdout.String())
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("expected dry run to leave %s absent, got %v", target, err)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"organise", "-mode", "copy", "-journal", journal, src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if b, err := os.ReadFile(target); err != nil || string(b) != "02" {
+		t.Errorf("expected %s to be copied, got %q (%v)", target, b, err)
+	}
+
+	if code := run([]string{"organise", "-undo", journal}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("expected undo to remove %s, got %v", target, err)
+	}
+}
+
+func TestOrganiseRunOrganiseUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{"organise"}, {"organise", "-mode", "teleport", "a", "b"}, {"organise", "-template", "{nope}", "a", "b"}} {
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("expected exit code %d for %v, got %d", exitUsage, args, code)
+		}
+	}
+}
+
+func TestOrganiseRunOrganiseProfile(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"[Group] Title OVA [BD 1080p].mkv": "",
+	})
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"organise", "-profile", "plex", "-nfo", src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	for _, name := range []string{"Title/Season 00/Title - s00e01.mkv", "Title/Season 00/Title - s00e01.nfo", "Title/tvshow.nfo"} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Errorf("expected %s to exist, got %v", name, err)
+		}
+	}
+
+	src, root = t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"[Group] Title - 05 [1080p].mkv": "",
+	})
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+	if code := run([]string{"organise", "-profile", "kodi", "-journal", journal, src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if _, err := os.Stat(filepath.Join(root, "Title", "Season 01", "Title S01E05.nfo")); err != nil {
+		t.Errorf("expected the kodi profile to write NFO sidecars, got %v", err)
+	}
+	if code := run([]string{"organise", "-undo", journal}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Errorf("expected undo to remove the sidecars and their directories, got %v", entries)
+	}
+
+	src, root = t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"[Group] Title S3 - 01 [1080p].mkv": "",
+	})
+	stderr.Reset()
+	if code := run([]string{"organise", "-profile", "plex-absolute", src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "skipped") {
+		t.Errorf("expected S3 - 01 to be skipped, got %q", stderr.String())
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Errorf("expected nothing organised, got %v", entries)
+	}
+
+	// -nfo without -profile keeps the paths of the template.
+	src, root = t.TempDir(), t.TempDir()
+	writeTestFiles(t, src, map[string]string{
+		"[Group] Title OVA [BD 1080p].mkv": "",
+	})
+	if code := run([]string{"organise", "-nfo", "-template", "{title}.{ext}", src, root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	for _, name := range []string{"Title OVA.mkv", "Title OVA.nfo"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			t.Errorf("expected %s to exist, got %v", name, err)
+		}
+	}
+
+	if code := run([]string{"organise", "-profile", "emby", src, root}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("expected exit code %d for an unknown profile, got %d", exitUsage, code)
+	}
+}
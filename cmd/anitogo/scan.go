@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// scanRecord is a line of the catalogue written by the scan command.
+type scanRecord struct {
+	Path     string            `json:"path"`
+	Size     int64             `json:"size"`
+	Elements *anitogo.Elements `json:"elements"`
+}
+
+// scanCSVHeader lists the columns of the CSV catalogue. Fields holding several values are joined with ";".
+var scanCSVHeader = []string{
+	"path", "size", "anime_title", "anime_season", "episode_number", "episode_title",
+	"release_group", "video_resolution", "source", "file_checksum", "file_extension",
+}
+
+type scanner struct {
+	root string
+	// Base name of the root, parsed as the outermost directory so that the root's own name gives context.
+	rootName       string
+	includes       []string
+	excludes       []string
+	followSymlinks bool
+	options        anitogo.Options
+
+	// Real paths of the directories already walked, to stop symlink loops.
+	visited map[string]bool
+
+	records     []scanRecord
+	skipped     int
+	unparseable []string
+	errors      []error
+}
+
+func runScan(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "jsonl", "catalogue format, \"jsonl\" or \"csv\"")
+	followSymlinks := flags.Bool("follow-symlinks", false, "walk into symlinked directories; symlinked files are always catalogued")
+	var includes, excludes stringsFlag
+	flags.Var(&includes, "include", "only catalogue files matching the glob; repeatable")
+	flags.Var(&excludes, "exclude", "skip files and directories matching the glob; repeatable")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anitogo scan [flags] <dir>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Globs without a \"/\" match the base name, others match the path relative to <dir>.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *format != "jsonl" && *format != "csv" {
+		fmt.Fprintf(stderr, "anitogo scan: unknown format %q\n", *format)
+		return exitUsage
+	}
+	for _, glob := range append(append([]string{}, includes...), excludes...) {
+		if _, err := path.Match(glob, ""); err != nil {
+			fmt.Fprintf(stderr, "anitogo scan: invalid glob %q\n", glob)
+			return exitUsage
+		}
+	}
+
+	s := &scanner{
+		root:           flags.Arg(0),
+		includes:       includes,
+		excludes:       excludes,
+		followSymlinks: *followSymlinks,
+		options:        anitogo.DefaultOptions,
+		visited:        map[string]bool{},
+	}
+	if err := s.scan(); err != nil {
+		fmt.Fprintf(stderr, "anitogo scan: %v\n", err)
+		return exitError
+	}
+
+	var err error
+	if *format == "csv" {
+		err = writeScanCSV(stdout, s.records)
+	} else {
+		err = writeScanJSONL(stdout, s.records)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "anitogo scan: %v\n", err)
+		return exitError
+	}
+
+	fmt.Fprintf(stderr, "Catalogued %d video files, skipped %d other files.\n", len(s.records), s.skipped)
+	if len(s.unparseable) > 0 {
+		fmt.Fprintf(stderr, "%d files have no recognisable anime title:\n", len(s.unparseable))
+		for _, p := range s.unparseable {
+			fmt.Fprintf(stderr, "  %s\n", p)
+		}
+	}
+	for _, err := range s.errors {
+		fmt.Fprintf(stderr, "anitogo scan: %v\n", err)
+	}
+	if len(s.errors) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func (s *scanner) scan() error {
+	info, err := os.Stat(s.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s.root)
+	}
+	abs, err := filepath.Abs(s.root)
+	if err != nil {
+		return err
+	}
+	s.rootName = filepath.Base(abs)
+	return s.walk(s.root, "")
+}
+
+// walk catalogues the directory dir, whose path relative to the root is rel.
+func (s *scanner) walk(dir, rel string) error {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if s.visited[realDir] {
+		return nil
+	}
+	s.visited[realDir] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		full := filepath.Join(dir, entry.Name())
+		entryRel := path.Join(rel, entry.Name())
+		if s.matches(s.excludes, entryRel) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			s.errors = append(s.errors, err)
+			continue
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			info, err = os.Stat(full)
+			if err != nil {
+				s.errors = append(s.errors, fmt.Errorf("broken symlink %s", full))
+				continue
+			}
+			if info.IsDir() && !s.followSymlinks {
+				continue
+			}
+		}
+
+		if info.IsDir() {
+			if err := s.walk(full, entryRel); err != nil {
+				s.errors = append(s.errors, err)
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if !anitogo.IsVideoExtension(filepath.Ext(entry.Name())) {
+			s.skipped++
+			continue
+		}
+		if len(s.includes) > 0 && !s.matches(s.includes, entryRel) {
+			s.skipped++
+			continue
+		}
+
+		e := anitogo.ParsePath(path.Join(s.rootName, entryRel), s.options)
+		s.records = append(s.records, scanRecord{Path: full, Size: info.Size(), Elements: e})
+		if e.AnimeTitle == "" {
+			s.unparseable = append(s.unparseable, full)
+		}
+	}
+	return nil
+}
+
+// matches returns true if rel matches any of the globs. Globs without a "/" are matched against the base name.
+func (s *scanner) matches(globs []string, rel string) bool {
+	for _, glob := range globs {
+		target := rel
+		if !strings.Contains(glob, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(glob, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func writeScanJSONL(w io.Writer, records []scanRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeScanCSV(w io.Writer, records []scanRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(scanCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		e := r.Elements
+		row := []string{
+			r.Path, strconv.FormatInt(r.Size, 10), e.AnimeTitle, strings.Join(e.AnimeSeason, ";"),
+			strings.Join(e.EpisodeNumber, ";"), e.EpisodeTitle, e.ReleaseGroup, e.VideoResolution,
+			strings.Join(e.Source, ";"), e.FileChecksum, e.FileExtension,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
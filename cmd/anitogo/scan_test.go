@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanRunScan(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"[Group] Title (BD 1080p)/[Group] Title - 01 (BD 1080p) [ABCD1234].mkv": "0123456789",
+		"[Group] Title (BD 1080p)/[Group] Title - 02 (BD 1080p) [ABCD1235].mkv": "01234",
+		"[Group] Title (BD 1080p)/[Group] Title - 01 (BD 1080p) [ABCD1234].ass": "",
+		"[Group] Title (BD 1080p)/Fonts/font.ttf":                               "",
+		"Other/Season 2/03.mp4": "",
+		"Other/archive.zip":     "",
+		"Samples/sample.mkv":    "",
+		"1234.mkv":              "",
+	})
+	if err := os.Symlink(root, filepath.Join(root, "Other", "loop")); err != nil {
+		t.Skip("symlinks are not supported:", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"scan", "-exclude", "Samples", "-follow-symlinks", root}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	var records []scanRecord
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var r scanRecord
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d: %s", len(records), stdout.String())
+	}
+	if records[0].Path != filepath.Join(root, "1234.mkv") || records[0].Elements.AnimeTitle != "" {
+		t.Errorf("expected 1234.mkv without a title, got %v", records[0])
+	}
+	if r := records[2]; r.Size != 10 || r.Elements.AnimeTitle != "Title" || r.Elements.ReleaseGroup != "Group" {
+		t.Errorf("expected episode 01 of \"Title\" with 10 bytes, got %v", r)
+	}
+	if r := records[1]; r.Elements.AnimeTitle != "Other" || len(r.Elements.AnimeSeason) != 1 || r.Elements.EpisodeNumber[0] != "03" {
+		t.Errorf("expected episode 03 of season 2 of \"Other\", got %v", r.Elements)
+	}
+	if !strings.Contains(stderr.String(), "Catalogued 4 video files, skipped 3 other files.") {
+		t.Errorf("expected a summary, got %s", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "1 files have no recognisable anime title:\n  "+filepath.Join(root, "1234.mkv")) {
+		t.Errorf("expected 1234.mkv to be unparseable, got %s", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"scan", "-format", "csv", "-include", "*.mp4", root}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	rows, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0][0] != "path" || rows[1][2] != "Other" || rows[1][4] != "03" {
+		t.Errorf("expected a header and the mp4 file, got %v", rows)
+	}
+
+	for _, args := range [][]string{
+		{"scan"},
+		{"scan", "-format", "xml", root},
+		{"scan", "-include", "[", root},
+		{"unknown"},
+	} {
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("%v: expected exit code 2, got %d", args, code)
+		}
+	}
+
+	// The root's own name gives the title of the files directly inside it.
+	titled := filepath.Join(t.TempDir(), "Some Title")
+	writeTestFiles(t, titled, map[string]string{"01.mkv": ""})
+	stdout.Reset()
+	stderr.Reset()
+	if code := run([]string{"scan", titled}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var r scanRecord
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Path != filepath.Join(titled, "01.mkv") || r.Elements.AnimeTitle != "Some Title" || r.Elements.EpisodeNumber[0] != "01" {
+		t.Errorf("expected episode 01 of \"Some Title\", got %v", r.Elements)
+	}
+
+	if code := run([]string{"scan", filepath.Join(root, "missing")}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code 1 for a missing directory, got %d", code)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo/server"
+)
+
+func runServe(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	maxBody := flags.Int64("max-body", server.DefaultOptions.MaxBodyBytes, "largest accepted request body in bytes")
+	maxBatch := flags.Int("max-batch", server.DefaultOptions.MaxBatch, "largest accepted batch of names")
+	shutdownTimeout := flags.Duration("shutdown-timeout", 10*time.Second, "time given to requests in progress on shutdown")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anitogo serve [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Serves POST /parse, POST /parse/path, GET /keywords and GET /health over HTTP.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *maxBody <= 0 || *maxBatch <= 0 {
+		fmt.Fprintln(stderr, "anitogo serve: -max-body and -max-batch must be positive")
+		return exitUsage
+	}
+
+	options := server.Options{MaxBodyBytes: *maxBody, MaxBatch: *maxBatch}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.NewHandler(options),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(stderr, "Listening on %s\n", *addr)
+	if err := server.Run(ctx, srv, *shutdownTimeout); err != nil {
+		fmt.Fprintf(stderr, "anitogo serve: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
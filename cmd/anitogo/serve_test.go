@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestServeRunServe(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{"serve", "extra"}, {"serve", "-max-body", "0"}, {"serve", "-max-batch", "-1"}} {
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("expected exit code %d for %v, got %d", exitUsage, args, code)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	defer l.Close()
+	if code := run([]string{"serve", "-addr", l.Addr().String()}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code %d for an address in use, got %d", exitError, code)
+	}
+}
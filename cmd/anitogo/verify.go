@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/nssteinbrenner/anitogo"
+	"github.com/nssteinbrenner/anitogo/verify"
+)
+
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	workers := flags.Int("workers", verify.DefaultOptions.Workers, "number of files hashed at the same time")
+	format := flags.String("format", "text", "output format, \"text\" or \"jsonl\"")
+	sfv := flags.String("sfv", "", "also hash files without a checksum and write every checksum to this SFV file")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anitogo verify [flags] <file or dir>...")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Compares the CRC32 of every video file with the checksum in its name, e.g \"[8F59F2BA]\".")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *format != "text" && *format != "jsonl" {
+		fmt.Fprintf(stderr, "anitogo verify: unknown format %q\n", *format)
+		return exitUsage
+	}
+
+	var paths []string
+	for _, arg := range flags.Args() {
+		info, err := os.Stat(arg)
+		if err != nil {
+			fmt.Fprintf(stderr, "anitogo verify: %v\n", err)
+			return exitError
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		s := &scanner{root: arg, options: anitogo.DefaultOptions, visited: map[string]bool{}}
+		if err := s.scan(); err != nil {
+			fmt.Fprintf(stderr, "anitogo verify: %v\n", err)
+			return exitError
+		}
+		for _, r := range s.records {
+			paths = append(paths, r.Path)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	options := verify.DefaultOptions
+	options.Workers = *workers
+	options.HashAll = *sfv != ""
+	results := verify.Files(ctx, paths, options)
+
+	code := exitOK
+	counts := map[verify.Status]int{}
+	enc := json.NewEncoder(stdout)
+	for _, r := range results {
+		counts[r.Status]++
+		if r.Status == verify.StatusMismatch || r.Status == verify.StatusError {
+			code = exitError
+		}
+		if *format == "jsonl" {
+			record := struct {
+				verify.Result
+				Error string `json:"error,omitempty"`
+			}{Result: r}
+			if r.Err != nil {
+				record.Error = r.Err.Error()
+			}
+			if err := enc.Encode(record); err != nil {
+				fmt.Fprintf(stderr, "anitogo verify: %v\n", err)
+				return exitError
+			}
+			continue
+		}
+		switch r.Status {
+		case verify.StatusMismatch:
+			fmt.Fprintf(stdout, "%-11s %s (expected %s, got %s)\n", r.Status, r.Path, r.Expected, r.Actual)
+		case verify.StatusError:
+			fmt.Fprintf(stdout, "%-11s %s: %v\n", r.Status, r.Path, r.Err)
+		default:
+			fmt.Fprintf(stdout, "%-11s %s\n", r.Status, r.Path)
+		}
+	}
+	fmt.Fprintf(stderr, "%d ok, %d mismatched, %d without checksum, %d unreadable.\n",
+		counts[verify.StatusOK], counts[verify.StatusMismatch], counts[verify.StatusNoChecksum], counts[verify.StatusError])
+
+	if *sfv != "" {
+		if err := writeSFVFile(*sfv, results); err != nil {
+			fmt.Fprintf(stderr, "anitogo verify: %v\n", err)
+			return exitError
+		}
+	}
+	return code
+}
+
+// writeSFVFile writes the results to an SFV file with paths relative to its directory.
+func writeSFVFile(name string, results []verify.Result) error {
+	dir, err := filepath.Abs(filepath.Dir(name))
+	if err != nil {
+		return err
+	}
+	relative := make([]verify.Result, len(results))
+	for i, r := range results {
+		relative[i] = r
+		if abs, err := filepath.Abs(r.Path); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				relative[i].Path = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := verify.WriteSFV(f, relative); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyRunVerify(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"Title/[Group] Title - 01 [CBF43926].mkv": "123456789",
+		"Title/[Group] Title - 02 [00000000].mkv": "123456789",
+		"Title/[Group] Title - 03.mkv":            "123456789",
+		"Title/[Group] Title - 03.ass":            "",
+	})
+	sfv := filepath.Join(root, "Title", "checksums.sfv")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"verify", "-sfv", sfv, root}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected exit code %d for a mismatch, got %d", exitError, code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ok ") || !strings.HasPrefix(lines[1], "mismatch ") ||
+		!strings.HasPrefix(lines[2], "no-checksum ") {
+		t.Errorf("expected ok, mismatch and no-checksum lines, got %q", stdout.String())
+	}
+	if !strings.Contains(lines[1], "expected 00000000, got CBF43926") {
+		t.Errorf("expected the checksums of the mismatch, got %q", lines[1])
+	}
+
+	b, err := os.ReadFile(sfv)
+	if err != nil {
+		t.Fatalf("expected the SFV file to be written, got %v", err)
+	}
+	if !strings.Contains(string(b), "\n[Group] Title - 03.mkv CBF43926\n") {
+		t.Errorf("expected relative paths in the SFV file, got %q", b)
+	}
+
+	stdout.Reset()
+	file := filepath.Join(root, "Title", "[Group] Title - 01 [CBF43926].mkv")
+	if code := run([]string{"verify", "-format", "jsonl", file}, &stdout, &stderr); code != exitOK {
+		t.Errorf("expected exit code %d, got %d", exitOK, code)
+	}
+	if !strings.Contains(stdout.String(), `"status":"ok"`) {
+		t.Errorf("expected a JSON line with status ok, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo"
+	"github.com/nssteinbrenner/anitogo/mediaserver"
+	"github.com/nssteinbrenner/anitogo/organise"
+	"github.com/nssteinbrenner/anitogo/verify"
+)
+
+// watchFile is the size and modification time of a file when it was last seen.
+type watchFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+
+	// Number of consecutive polls the file was seen with this size and modification time.
+	Polls int `json:"polls,omitempty"`
+
+	// Target the file was organised to when a later action failed, so the retry does not organise it again.
+	Organised string `json:"organised,omitempty"`
+}
+
+// watchState is persisted between polls so files are not processed twice across restarts.
+type watchState struct {
+	Processed map[string]watchFile `json:"processed"`
+	Pending   map[string]watchFile `json:"pending"`
+}
+
+// watchEvent is written to stdout by the json action for every processed file.
+type watchEvent struct {
+	Path     string            `json:"path"`
+	Size     int64             `json:"size"`
+	Elements *anitogo.Elements `json:"elements"`
+	Checksum verify.Status     `json:"checksum,omitempty"`
+	Target   string            `json:"target,omitempty"`
+}
+
+// errWatchChecksumMismatch is returned by process for a file whose CRC32 does not match its name.
+// Such files are not retried since hashing them again gives the same result.
+var errWatchChecksumMismatch = errors.New("checksum mismatch")
+
+type watcher struct {
+	root        string
+	statePath   string
+	stablePolls int
+	options     anitogo.Options
+
+	// Actions, run in this order.
+	verify    bool
+	organiser *organise.Organiser
+	profile   *mediaserver.Profile
+	journal   string
+	json      bool
+	command   string
+
+	stdout, stderr io.Writer
+	state          watchState
+}
+
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	interval := flags.Duration("interval", 10*time.Second, "time between two polls of the directory")
+	stablePolls := flags.Int("stable", 2, "number of polls a file must keep its size and modification time before it is processed")
+	statePath := flags.String("state", "", "file remembering the processed files across restarts (default <dir>/.anitogo-watch.json)")
+	once := flags.Bool("once", false, "poll once and exit, e.g when run from cron")
+	verifyFlag := flags.Bool("verify", false, "verify the CRC32 in the file name; mismatched files get no further action")
+	organiseRoot := flags.String("organise", "", "organise files under this root")
+	template := flags.String("template", organise.DefaultTemplate, "naming template used with -organise")
+	profileName := flags.String("profile", "", "media server profile used with -organise instead of -template")
+	mode := flags.String("mode", string(organise.ModeMove), "\"move\", \"copy\", \"hardlink\" or \"symlink\", used with -organise")
+	journal := flags.String("journal", "", "journal of the operations of -organise, see \"anitogo organise -undo\"")
+	jsonFlag := flags.Bool("json", false, "write a JSON line per processed file to stdout")
+	command := flags.String("exec", "", "run this shell command for every processed file, with the parsed fields in ANITOGO_* environment variables")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anitogo watch [flags] <dir>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Polls <dir> for new video files and runs the actions on each once its size is stable.")
+		fmt.Fprintln(stderr, "The command run by -exec gets ANITOGO_PATH, ANITOGO_TARGET, ANITOGO_CHECKSUM_STATUS and one")
+		fmt.Fprintln(stderr, "variable per parsed element, e.g ANITOGO_ANIME_TITLE and ANITOGO_EPISODE_NUMBER.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *interval <= 0 || *stablePolls < 1 {
+		fmt.Fprintln(stderr, "anitogo watch: -interval and -stable must be positive")
+		return exitUsage
+	}
+	if !*verifyFlag && *organiseRoot == "" && !*jsonFlag && *command == "" {
+		fmt.Fprintln(stderr, "anitogo watch: no action, use -verify, -organise, -json or -exec")
+		return exitUsage
+	}
+
+	w := &watcher{
+		root:        flags.Arg(0),
+		statePath:   *statePath,
+		stablePolls: *stablePolls,
+		options:     anitogo.DefaultOptions,
+		verify:      *verifyFlag,
+		journal:     *journal,
+		json:        *jsonFlag,
+		command:     *command,
+		stdout:      stdout,
+		stderr:      stderr,
+	}
+	if w.statePath == "" {
+		w.statePath = filepath.Join(w.root, ".anitogo-watch.json")
+	}
+	if *organiseRoot != "" {
+		var t *organise.Template
+		var err error
+		if *profileName != "" {
+			if w.profile, err = mediaserver.LookupProfile(*profileName); err == nil {
+				t = w.profile.Template
+			}
+		} else {
+			t, err = organise.ParseTemplate(*template)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "anitogo watch: %v\n", err)
+			return exitUsage
+		}
+		m, err := organise.ParseMode(*mode)
+		if err != nil {
+			fmt.Fprintf(stderr, "anitogo watch: %v\n", err)
+			return exitUsage
+		}
+		w.organiser = &organise.Organiser{Root: *organiseRoot, Template: t, Mode: m}
+	}
+	if err := w.loadState(); err != nil {
+		fmt.Fprintf(stderr, "anitogo watch: %v\n", err)
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx); err != nil {
+			fmt.Fprintf(stderr, "anitogo watch: %v\n", err)
+			if *once {
+				return exitError
+			}
+		}
+		if *once {
+			return exitOK
+		}
+		select {
+		case <-ctx.Done():
+			return exitOK
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *watcher) loadState() error {
+	w.state = watchState{Processed: map[string]watchFile{}, Pending: map[string]watchFile{}}
+	data, err := os.ReadFile(w.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &w.state); err != nil {
+		return fmt.Errorf("invalid state %s: %w", w.statePath, err)
+	}
+	if w.state.Processed == nil {
+		w.state.Processed = map[string]watchFile{}
+	}
+	if w.state.Pending == nil {
+		w.state.Pending = map[string]watchFile{}
+	}
+	return nil
+}
+
+// saveState writes the state through a temporary file so a crash never leaves it truncated.
+func (w *watcher) saveState() error {
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := w.statePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, w.statePath)
+}
+
+// poll scans the directory once, processes the files that have been stable for enough polls and saves the state.
+func (w *watcher) poll(ctx context.Context) error {
+	s := &scanner{root: w.root, options: w.options, visited: map[string]bool{}}
+	if err := s.scan(); err != nil {
+		return err
+	}
+	for _, err := range s.errors {
+		fmt.Fprintf(w.stderr, "anitogo watch: %v\n", err)
+	}
+
+	// Files organised under the watched directory are the output of earlier polls, not new files.
+	organised := ""
+	if w.organiser != nil {
+		organised, _ = filepath.Abs(w.organiser.Root)
+	}
+
+	seen := map[string]bool{}
+	var ready []scanRecord
+	readyFiles := map[string]watchFile{}
+	for _, r := range s.records {
+		if abs, err := filepath.Abs(r.Path); organised != "" && err == nil && isWithin(abs, organised) {
+			continue
+		}
+		seen[r.Path] = true
+		info, err := os.Stat(r.Path)
+		if err != nil {
+			continue
+		}
+		current := watchFile{Size: info.Size(), ModTime: info.ModTime().UTC()}
+		if done, found := w.state.Processed[r.Path]; found && sameWatchFile(done, current) {
+			continue
+		}
+		if pending, found := w.state.Pending[r.Path]; found && sameWatchFile(pending, current) {
+			current.Polls = pending.Polls
+			current.Organised = pending.Organised
+		}
+		current.Polls++
+		if current.Polls < w.stablePolls {
+			w.state.Pending[r.Path] = current
+			continue
+		}
+		delete(w.state.Pending, r.Path)
+		readyFiles[r.Path] = current
+		ready = append(ready, r)
+	}
+	for _, files := range []map[string]watchFile{w.state.Processed, w.state.Pending} {
+		for p := range files {
+			if !seen[p] {
+				delete(files, p)
+			}
+		}
+	}
+
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Path < ready[j].Path })
+	for _, r := range ready {
+		file := readyFiles[r.Path]
+		if ctx.Err() != nil {
+			// Files not processed yet are picked up again by the next run.
+			w.state.Pending[r.Path] = file
+			continue
+		}
+		err := w.process(r, &file)
+		if err != nil {
+			fmt.Fprintf(w.stderr, "anitogo watch: %s: %v\n", r.Path, err)
+		}
+		if err != nil && !errors.Is(err, errWatchChecksumMismatch) {
+			// The file stays stable, so it is retried by the next poll.
+			w.state.Pending[r.Path] = file
+			continue
+		}
+		file.Polls = 0
+		file.Organised = ""
+		w.state.Processed[r.Path] = file
+	}
+	return w.saveState()
+}
+
+func sameWatchFile(a, b watchFile) bool {
+	return a.Size == b.Size && a.ModTime.Equal(b.ModTime)
+}
+
+// isWithin returns true if path is dir or is below it. Both paths must be absolute.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// process runs the actions on a file that stopped changing. Actions that succeeded are recorded in file
+// so a retry after a later action failed skips them.
+func (w *watcher) process(r scanRecord, file *watchFile) error {
+	event := watchEvent{Path: r.Path, Size: r.Size, Elements: r.Elements}
+
+	if w.verify {
+		options := verify.DefaultOptions
+		options.Parser = w.options
+		result := verify.File(r.Path, options)
+		event.Checksum = result.Status
+		switch result.Status {
+		case verify.StatusError:
+			return result.Err
+		case verify.StatusMismatch:
+			return fmt.Errorf("%w, expected %s, got %s", errWatchChecksumMismatch, result.Expected, result.Actual)
+		}
+	}
+
+	if w.organiser != nil && file.Organised != "" {
+		event.Target = file.Organised
+	} else if w.organiser != nil {
+		e := r.Elements
+		if w.profile != nil {
+			var err error
+			if e, err = w.profile.Elements(e); err != nil {
+				return err
+			}
+		}
+		plan := w.organiser.Plan([]organise.Item{{Path: r.Path, Elements: e}})
+		if len(plan.Skipped) > 0 {
+			return errors.New(plan.Skipped[0].Reason)
+		}
+		if len(plan.Conflicts) > 0 {
+			return fmt.Errorf("conflict at %s: %s", plan.Conflicts[0].Target, plan.Conflicts[0].Reason)
+		}
+		if err := organise.Execute(plan, organise.ExecuteOptions{Journal: w.journal}); err != nil {
+			return err
+		}
+		event.Target = plan.Operations[0].Target
+		file.Organised = event.Target
+		if w.profile != nil && w.profile.NFO {
+			if err := writeSidecars(w.profile, event.Target, e, w.journal, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if w.json {
+		if err := json.NewEncoder(w.stdout).Encode(event); err != nil {
+			return err
+		}
+	}
+
+	if w.command != "" {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", w.command)
+		} else {
+			cmd = exec.Command("sh", "-c", w.command)
+		}
+		cmd.Env = append(os.Environ(), watchEnvironment(event)...)
+		cmd.Stdout, cmd.Stderr = w.stdout, w.stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", w.command, err)
+		}
+	}
+	return nil
+}
+
+// watchEnvironment returns the environment variables describing a processed file. Every element
+// holding a string or a list of strings is passed as ANITOGO_<JSON NAME>, lists being joined with ",".
+func watchEnvironment(event watchEvent) []string {
+	env := []string{
+		"ANITOGO_PATH=" + event.Path,
+		"ANITOGO_TARGET=" + event.Target,
+		"ANITOGO_CHECKSUM_STATUS=" + string(event.Checksum),
+	}
+	data, err := json.Marshal(event.Elements)
+	if err != nil {
+		return env
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return env
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var value string
+		switch v := fields[name].(type) {
+		case string:
+			value = v
+		case []interface{}:
+			var values []string
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+			if len(values) != len(v) {
+				continue
+			}
+			value = strings.Join(values, ",")
+		default:
+			continue
+		}
+		env = append(env, "ANITOGO_"+strings.ToUpper(name)+"="+value)
+	}
+	return env
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWatchRunWatch(t *testing.T) {
+	dir, root := t.TempDir(), t.TempDir()
+	state := filepath.Join(t.TempDir(), "state.json")
+	writeTestFiles(t, dir, map[string]string{
+		"[Group] Title - 01 [CBF43926].mkv": "123456789",
+		"[Group] Title - 02 [00000000].mkv": "123456789",
+	})
+	args := []string{"watch", "-once", "-state", state, "-verify", "-json", "-organise", root, "-mode", "copy", dir}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no file to be processed before its size is stable, got %q", stdout.String())
+	}
+
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	var event watchEvent
+	if err := json.Unmarshal(stdout.Bytes(), &event); err != nil {
+		t.Fatalf("expected a single JSON line, got %q (%v)", stdout.String(), err)
+	}
+	expected := filepath.Join(root, "Title", "Season 01", "Title - S01E01.mkv")
+	if event.Checksum != "ok" || event.Target != expected {
+		t.Errorf("expected a verified file copied to %s, got %+v", expected, event)
+	}
+	if !strings.Contains(stderr.String(), "checksum mismatch") {
+		t.Errorf("expected the mismatched file to be reported, got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run(args, &stdout, &stderr); code != exitOK || stdout.Len() != 0 {
+		t.Errorf("expected processed files to be skipped, got %d %q", code, stdout.String())
+	}
+
+	// A file that changes is processed again once it is stable.
+	writeTestFiles(t, dir, map[string]string{"[Group] Title - 03.mkv": ""})
+	args = []string{"watch", "-once", "-stable", "1", "-state", state, "-json", dir}
+	if code := run(args, &stdout, &stderr); code != exitOK || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("expected the new file to be processed, got %d %q", code, stdout.String())
+	}
+}
+
+func TestWatchRunWatchExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command uses sh")
+	}
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"Show/[Group] Show - 05 [1080p].mkv": ""})
+	out := filepath.Join(t.TempDir(), "out.txt")
+	command := `printf '%s|%s|%s' "$ANITOGO_ANIME_TITLE" "$ANITOGO_EPISODE_NUMBER" "$ANITOGO_VIDEO_RESOLUTION" > ` + out
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"watch", "-once", "-stable", "1", "-exec", command, dir}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	b, err := os.ReadFile(out)
+	if err != nil || string(b) != "Show|05|1080p" {
+		t.Errorf("expected the parsed fields in the environment, got %q (%v)", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".anitogo-watch.json")); err != nil {
+		t.Errorf("expected the default state file, got %v", err)
+	}
+}
+
+func TestWatchRunWatchRetry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command uses sh")
+	}
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"[Group] Show - 05 [1080p].mkv": ""})
+	marker := filepath.Join(t.TempDir(), "ready")
+	args := []string{"watch", "-once", "-stable", "1", "-json", "-exec", "test -e " + marker, dir}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(args, &stdout, &stderr); code != exitOK || !strings.Contains(stderr.String(), "exit status 1") {
+		t.Fatalf("expected the command to fail, got %d %q", code, stderr.String())
+	}
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if code := run(args, &stdout, &stderr); code != exitOK || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("expected the failed file to be retried, got %d %q", code, stdout.String())
+	}
+	stdout.Reset()
+	if code := run(args, &stdout, &stderr); code != exitOK || stdout.Len() != 0 {
+		t.Errorf("expected the file to be processed once it succeeded, got %d %q", code, stdout.String())
+	}
+
+	// A retry does not organise the file again, and files organised under the watched directory are ignored.
+	dir = t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"[Group] Show - 06 [1080p].mkv": ""})
+	os.Remove(marker)
+	args = []string{"watch", "-once", "-stable", "1", "-organise", filepath.Join(dir, "Library"), "-mode", "copy", "-json", "-exec", "test -e " + marker, dir}
+	if code := run(args, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if code := run(args, &stdout, &stderr); code != exitOK || stderr.Len() != 0 || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("expected the retry to only run the remaining actions, got %d %q %q", code, stdout.String(), stderr.String())
+	}
+	var event watchEvent
+	if err := json.Unmarshal(stdout.Bytes(), &event); err != nil || !strings.HasPrefix(event.Target, filepath.Join(dir, "Library")) {
+		t.Errorf("expected the target of the first organise, got %+v (%v)", event, err)
+	}
+}
+
+func TestWatchRunWatchUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{{"watch"}, {"watch", "dir"}, {"watch", "-json", "-stable", "0", "dir"}} {
+		if code := run(args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("expected exit code %d for %v, got %d", exitUsage, args, code)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package anitogo
+
+import "strings"
+
+// CompanionKind is the kind of file shipped alongside a video file.
+type CompanionKind string
+
+const (
+	// CompanionKindSubtitle is used for subtitle files, e.g "Title - 05.eng.srt".
+	CompanionKindSubtitle CompanionKind = "subtitle"
+
+	// CompanionKindAudio is used for external audio tracks, e.g "Title - 05.mka".
+	CompanionKindAudio CompanionKind = "audio"
+
+	// CompanionKindFont is used for fonts used by the subtitles, usually in a "Fonts" directory.
+	CompanionKindFont CompanionKind = "font"
+)
+
+var (
+	companionSubtitleExtensions = []string{"ASS", "IDX", "SRT", "SSA", "SUB", "SUP", "VTT"}
+	companionAudioExtensions    = []string{"AAC", "AC3", "DTS", "EAC3", "FLAC", "M4A", "MKA", "MP3", "OGG", "OPUS", "WAV", "WMA"}
+	companionFontExtensions     = []string{"OTF", "TTC", "TTF", "WOFF", "WOFF2"}
+	companionFontDirectories    = []string{"ATTACHMENTS", "FONTS"}
+
+	// companionTrackTags describe a track in the suffix of a companion, e.g "forced" in "Title - 05.jpn.forced.ass".
+	companionTrackTags = []string{
+		"CC", "COMMENTARY", "DEFAULT", "DIALOGUE", "FORCED", "FULL", "HI", "KARAOKE", "SDH",
+		"SIGNS", "SIGNS&SONGS", "SONGS", "TITLES"}
+)
+
+// companionMaxSuffixParts is the number of dot separated parts a companion suffix can have.
+const companionMaxSuffixParts = 3
+
+// Companion is a subtitle, audio track or font file shipped alongside a video file.
+type Companion struct {
+	// Path of the file.
+	Path string `json:"path"`
+
+	Kind CompanionKind `json:"kind"`
+
+	// Language found in the suffix, e.g English for ".eng" or ".en".
+	Language *Language `json:"language,omitempty"`
+
+	// Track tags found in the suffix in lowercase, e.g "forced" or "signs".
+	Tags []string `json:"tags,omitempty"`
+
+	// Suffix between the name of the video and the extension, e.g ".jpn.forced".
+	Suffix string `json:"suffix,omitempty"`
+
+	// Elements parsed from the name without its suffix. Nil for fonts.
+	Elements *Elements `json:"elements,omitempty"`
+}
+
+// MediaSet is a video file and its companions.
+type MediaSet struct {
+	Video      string       `json:"video"`
+	Elements   *Elements    `json:"elements"`
+	Companions []*Companion `json:"companions,omitempty"`
+}
+
+// ParseCompanion parses the companion file at path with its parent directories as context.
+// It returns false if the file is not a subtitle, audio track or font, e.g a video file.
+func ParseCompanion(path string, options Options) (*Companion, bool) {
+	parts := splitPath(path)
+	if len(parts) == 0 {
+		return nil, false
+	}
+	base := parts[len(parts)-1]
+	stem, ext := base, ""
+	if i := strings.LastIndex(base, "."); i > 0 {
+		stem, ext = base[:i], strings.ToUpper(base[i+1:])
+	}
+
+	inFontDirectory := len(parts) > 1 && checkInList(companionFontDirectories, strings.ToUpper(parts[len(parts)-2]))
+
+	c := &Companion{Path: path}
+	switch {
+	case checkInList(companionFontExtensions, ext) || (inFontDirectory && ext != "" && !IsVideoExtension(ext)):
+		c.Kind = CompanionKindFont
+		return c, true
+	case checkInList(companionSubtitleExtensions, ext):
+		c.Kind = CompanionKindSubtitle
+	case checkInList(companionAudioExtensions, ext):
+		c.Kind = CompanionKindAudio
+	default:
+		return nil, false
+	}
+
+	// The name is parsed without its extension since most companion extensions are not known to Parse.
+	parts[len(parts)-1] = c.parseSuffix(stem)
+	c.Elements = ParsePath(strings.Join(parts, "/"), options)
+	c.Elements.FileName = base
+	if options.ParseFileExtension {
+		c.Elements.FileExtension = base[len(base)-len(ext):]
+	}
+	return c, true
+}
+
+// parseSuffix moves the language and track tags at the end of stem into the companion and returns the rest of stem.
+func (c *Companion) parseSuffix(stem string) string {
+	for n := 0; n < companionMaxSuffixParts; n++ {
+		i := strings.LastIndex(stem, ".")
+		if i <= 0 {
+			break
+		}
+		part := stem[i+1:]
+		upper := strings.ToUpper(part)
+		if checkInList(companionTrackTags, upper) {
+			c.Tags = append([]string{strings.ToLower(part)}, c.Tags...)
+		} else if lang, found := companionLanguage(upper); found && c.Language == nil {
+			lang.Raw = part
+			if c.Kind == CompanionKindSubtitle {
+				lang.Track = LanguageTrackSubtitles
+			} else {
+				lang.Track = LanguageTrackAudio
+			}
+			c.Language = &lang
+		} else {
+			break
+		}
+		c.Suffix = stem[i:] + c.Suffix
+		stem = stem[:i]
+	}
+	return stem
+}
+
+// companionLanguage finds the language of a suffix part. Ambiguous keywords like "EN" are accepted
+// since a suffix holds nothing else, and so are the ISO 639 codes of every known language.
+func companionLanguage(part string) (Language, bool) {
+	if entry, found := languageTable[part]; found {
+		return entry.language, true
+	}
+	lower := strings.ToLower(part)
+	for _, entry := range languageTable {
+		lang := entry.language
+		if lang.Region == "" && lang.Script == "" && lang.Track == LanguageTrackUnknown &&
+			(lang.ISO6391 == lower || lang.ISO6393 == lower) {
+			return lang, true
+		}
+	}
+	return Language{}, false
+}
+
+// MatchCompanions sorts paths into video files and companions and links each companion to its video.
+//
+// A subtitle or audio track belongs to the video in the same directory with the same name without its suffix,
+// e.g "Title - 05.eng.srt" to "Title - 05.mkv". Otherwise it belongs to the video with the same title, season and
+// episode, preferring videos in the same directory and then from the same release group. A font belongs to every
+// video in its directory, or in the parent of its "Fonts" directory, and below.
+//
+// It returns a set for every video in the order of paths, and the companions that match no video or several.
+// Paths that are neither videos nor companions are ignored.
+func MatchCompanions(paths []string, options Options) ([]*MediaSet, []*Companion) {
+	var sets []*MediaSet
+	var companions []*Companion
+	for _, p := range paths {
+		base := p[strings.LastIndexAny(p, "/\\")+1:]
+		if i := strings.LastIndex(base, "."); i > 0 && IsVideoExtension(base[i+1:]) {
+			sets = append(sets, &MediaSet{Video: p, Elements: ParsePath(p, options)})
+		} else if c, found := ParseCompanion(p, options); found {
+			companions = append(companions, c)
+		}
+	}
+
+	var unmatched []*Companion
+	for _, c := range companions {
+		if c.Kind == CompanionKindFont {
+			matched := false
+			dir := companionFontRoot(c.Path)
+			for _, s := range sets {
+				if dir == "" || strings.HasPrefix(s.Video, dir) {
+					s.Companions = append(s.Companions, c)
+					matched = true
+				}
+			}
+			if !matched {
+				unmatched = append(unmatched, c)
+			}
+			continue
+		}
+		if s := matchCompanion(c, sets); s != nil {
+			s.Companions = append(s.Companions, c)
+		} else {
+			unmatched = append(unmatched, c)
+		}
+	}
+	return sets, unmatched
+}
+
+// matchCompanion returns the set a subtitle or audio companion belongs to, or nil if there is none or several.
+func matchCompanion(c *Companion, sets []*MediaSet) *MediaSet {
+	dir, stem := companionDir(c.Path), companionStem(c)
+	var candidates []*MediaSet
+	for _, s := range sets {
+		if companionDir(s.Video) != dir {
+			continue
+		}
+		videoStem := s.Video[len(dir):]
+		videoStem = videoStem[:strings.LastIndex(videoStem, ".")]
+		if strings.EqualFold(videoStem, stem) {
+			return s
+		}
+	}
+
+	if c.Elements.AnimeTitle == "" {
+		return nil
+	}
+	for _, s := range sets {
+		if s.Elements.SameEpisode(c.Elements) {
+			candidates = append(candidates, s)
+		}
+	}
+	filters := []func(*MediaSet) bool{
+		func(s *MediaSet) bool { return companionDir(s.Video) == dir },
+		func(s *MediaSet) bool { return strings.EqualFold(s.Elements.ReleaseGroup, c.Elements.ReleaseGroup) },
+	}
+	for _, keep := range filters {
+		var kept []*MediaSet
+		for _, s := range candidates {
+			if keep(s) {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) > 0 {
+			candidates = kept
+		}
+	}
+	if len(candidates) != 1 {
+		return nil
+	}
+	return candidates[0]
+}
+
+// companionDir returns the directory of path including its trailing separator, or "" for a bare file name.
+func companionDir(path string) string {
+	return path[:strings.LastIndexAny(path, "/\\")+1]
+}
+
+// companionStem returns the name of a companion without its suffix and extension.
+func companionStem(c *Companion) string {
+	base := c.Path[len(companionDir(c.Path)):]
+	base = base[:strings.LastIndex(base, ".")]
+	return strings.TrimSuffix(base, c.Suffix)
+}
+
+// companionFontRoot returns the directory whose videos use a font: the parent of its "Fonts" directory,
+// or its own directory.
+func companionFontRoot(path string) string {
+	dir := companionDir(path)
+	if dir == "" {
+		return ""
+	}
+	name := dir[strings.LastIndexAny(dir[:len(dir)-1], "/\\")+1 : len(dir)-1]
+	if checkInList(companionFontDirectories, strings.ToUpper(name)) {
+		return dir[:len(dir)-len(name)-1]
+	}
+	return dir
+}
@@ -0,0 +1,118 @@
+package anitogo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompanionParseCompanion(t *testing.T) {
+	tests := []struct {
+		path     string
+		kind     CompanionKind
+		language string
+		tags     []string
+		suffix   string
+		episode  string
+	}{
+		{"Title - 05.ass", CompanionKindSubtitle, "", nil, "", "05"},
+		{"Title - 05.eng.srt", CompanionKindSubtitle, "en", nil, ".eng", "05"},
+		{"Title - 05.en.ass", CompanionKindSubtitle, "en", nil, ".en", "05"},
+		{"Title - 05.jpn.forced.ass", CompanionKindSubtitle, "ja", []string{"forced"}, ".jpn.forced", "05"},
+		{"Title - 05.signs.ass", CompanionKindSubtitle, "", []string{"signs"}, ".signs", "05"},
+		{"Title - 05.pt-BR.Signs&Songs.ass", CompanionKindSubtitle, "pt-BR", []string{"signs&songs"}, ".pt-BR.Signs&Songs", "05"},
+		{"[Group] Title (BD)/Title - 05.mka", CompanionKindAudio, "", nil, "", "05"},
+		{"Title - 05.jpn.flac", CompanionKindAudio, "ja", nil, ".jpn", "05"},
+		{"Title.S01E05.1080p.srt", CompanionKindSubtitle, "", nil, "", "05"},
+	}
+	for _, test := range tests {
+		c, found := ParseCompanion(test.path, DefaultOptions)
+		if !found {
+			t.Errorf("expected %q to be a companion", test.path)
+			continue
+		}
+		if c.Kind != test.kind {
+			t.Errorf("expected kind %q, got %q", test.kind, c.Kind)
+		}
+		language := ""
+		if c.Language != nil {
+			language = c.Language.Tag()
+		}
+		if language != test.language {
+			t.Errorf("expected language %q for %q, got %q", test.language, test.path, language)
+		}
+		if !reflect.DeepEqual(c.Tags, test.tags) {
+			t.Errorf("expected tags %v for %q, got %v", test.tags, test.path, c.Tags)
+		}
+		if c.Suffix != test.suffix {
+			t.Errorf("expected suffix %q, got %q", test.suffix, c.Suffix)
+		}
+		if c.Elements.AnimeTitle != "Title" || !reflect.DeepEqual(c.Elements.EpisodeNumber, []string{test.episode}) {
+			t.Errorf("expected Title episode %s for %q, got %q %v", test.episode, test.path, c.Elements.AnimeTitle, c.Elements.EpisodeNumber)
+		}
+	}
+}
+
+func TestCompanionParseCompanionKinds(t *testing.T) {
+	tests := map[string]bool{
+		"Release/Fonts/arial.ttf":  true,
+		"Release/Fonts/Arial.OTF":  true,
+		"Release/Fonts/font.dat":   true,
+		"Release/Title - 05.mkv":   false,
+		"Release/Title - 05.nfo":   false,
+		"Release/Fonts/sample.mkv": false,
+	}
+	for path, expected := range tests {
+		c, found := ParseCompanion(path, DefaultOptions)
+		if found != expected {
+			t.Errorf("expected %t for %q, got %t", expected, path, found)
+		}
+		if found && c.Kind != CompanionKindFont {
+			t.Errorf("expected %q to be a font, got %q", path, c.Kind)
+		}
+	}
+}
+
+func TestCompanionMatchCompanions(t *testing.T) {
+	paths := []string{
+		"Release/[Group] Title - 01 [1080p].mkv",
+		"Release/[Group] Title - 02 [1080p].mkv",
+		"Release/[Group] Title - 01 [1080p].eng.ass",
+		"Release/[Group] Title - 01 [1080p].mka",
+		"Release/Subs/Title - 02.jpn.forced.ass",
+		"Release/Fonts/arial.ttf",
+		"Release/Title - 03.ass",
+		"Release/notes.txt",
+	}
+	sets, unmatched := MatchCompanions(paths, DefaultOptions)
+	if len(sets) != 2 {
+		t.Fatalf("expected 2 sets, got %d", len(sets))
+	}
+	expected := map[string][]string{
+		"Release/[Group] Title - 01 [1080p].mkv": {"Release/[Group] Title - 01 [1080p].eng.ass", "Release/[Group] Title - 01 [1080p].mka", "Release/Fonts/arial.ttf"},
+		"Release/[Group] Title - 02 [1080p].mkv": {"Release/Subs/Title - 02.jpn.forced.ass", "Release/Fonts/arial.ttf"},
+	}
+	for _, s := range sets {
+		var got []string
+		for _, c := range s.Companions {
+			got = append(got, c.Path)
+		}
+		if !reflect.DeepEqual(got, expected[s.Video]) {
+			t.Errorf("expected companions %v for %q, got %v", expected[s.Video], s.Video, got)
+		}
+	}
+	if len(unmatched) != 1 || unmatched[0].Path != "Release/Title - 03.ass" {
+		t.Errorf("expected Release/Title - 03.ass to be unmatched, got %v", unmatched)
+	}
+}
+
+func TestCompanionMatchCompanionsAmbiguous(t *testing.T) {
+	paths := []string{
+		"[A] Title - 01.mkv",
+		"[B] Title - 01.mkv",
+		"Subs/Title - 01.ass",
+	}
+	_, unmatched := MatchCompanions(paths, DefaultOptions)
+	if len(unmatched) != 1 {
+		t.Errorf("expected the subtitle matching two videos to be unmatched, got %v", unmatched)
+	}
+}
@@ -0,0 +1,196 @@
+package anitogo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrDifferentEpisode is returned by CompareReleases when the two Elements do not describe the same episode.
+var ErrDifferentEpisode = errors.New("anitogo: elements describe different episodes")
+
+// ComparisonReason is the attribute that decided a comparison between two releases.
+type ComparisonReason string
+
+const (
+	// ComparisonReasonNone is used when neither release is preferred.
+	ComparisonReasonNone ComparisonReason = ""
+
+	// ComparisonReasonReleaseVersion is used when releases from the same group have different versions, e.g "07v2" and "07".
+	ComparisonReasonReleaseVersion ComparisonReason = "release_version"
+
+	// ComparisonReasonRevision is used when one release is nuked, or when releases from the same group
+	// have different scene revision markers, e.g "REPACK2" and "REPACK".
+	ComparisonReasonRevision ComparisonReason = "revision"
+
+	// ComparisonReasonResolution is used when the releases have different video resolutions.
+	ComparisonReasonResolution ComparisonReason = "resolution"
+
+	// ComparisonReasonSourceType is used when the releases come from different sources, e.g BD and TV.
+	ComparisonReasonSourceType ComparisonReason = "source_type"
+
+	// ComparisonReasonBitDepth is used when the releases have different video bit depths, e.g 10-bit and 8-bit.
+	ComparisonReasonBitDepth ComparisonReason = "bit_depth"
+
+	// ComparisonReasonLosslessAudio is used when only one of the releases has lossless audio, e.g FLAC.
+	ComparisonReasonLosslessAudio ComparisonReason = "lossless_audio"
+)
+
+// Comparison is the outcome of comparing two releases of the same episode.
+type Comparison struct {
+	// 1 if the first release supersedes the second, -1 if the second supersedes the first
+	// and 0 if neither is preferred.
+	Result int `json:"result"`
+
+	// Attribute that decided the comparison. Empty when Result is 0.
+	Reason ComparisonReason `json:"reason,omitempty"`
+}
+
+// Supersedes returns true if the first release should replace the second.
+func (c Comparison) Supersedes() bool {
+	return c.Result > 0
+}
+
+// sourceTypeRanks orders source types from the least to the most preferred.
+var sourceTypeRanks = map[SourceType]int{
+	SourceTypeVHS:       1,
+	SourceTypeLaserDisc: 2,
+	SourceTypeTV:        3,
+	SourceTypeDVD:       4,
+	SourceTypeWEBRip:    5,
+	SourceTypeWEBDL:     6,
+	SourceTypeBD:        7,
+}
+
+// CompareReleases compares two releases of the same episode and reports which one supersedes the other.
+//
+// Releases from the same group are first ordered by release version, e.g "07v2" over "07",
+// and then by scene revision markers, e.g "REPACK2" over "REPACK". A nuked release always loses.
+// Version numbers are not comparable between groups, so releases from different groups
+// and releases with equal versions are ordered by resolution, source type, bit depth and lossless audio.
+//
+// ErrDifferentEpisode is returned if the anime titles, seasons or episode numbers do not match.
+func CompareReleases(a, b *Elements) (Comparison, error) {
+	if !a.SameEpisode(b) {
+		return Comparison{}, ErrDifferentEpisode
+	}
+
+	if nukedA, nukedB := a.Revision != nil && a.Revision.Nuked, b.Revision != nil && b.Revision.Nuked; nukedA != nukedB {
+		if nukedA {
+			return Comparison{Result: -1, Reason: ComparisonReasonRevision}, nil
+		}
+		return Comparison{Result: 1, Reason: ComparisonReasonRevision}, nil
+	}
+
+	if strings.EqualFold(a.ReleaseGroup, b.ReleaseGroup) {
+		if r := compareInts(releaseVersion(a), releaseVersion(b)); r != 0 {
+			return Comparison{Result: r, Reason: ComparisonReasonReleaseVersion}, nil
+		}
+		if r := compareInts(revisionCounter(a), revisionCounter(b)); r != 0 {
+			return Comparison{Result: r, Reason: ComparisonReasonRevision}, nil
+		}
+	}
+
+	if a.Resolution != nil && b.Resolution != nil {
+		if r := a.Resolution.Compare(*b.Resolution); r != 0 {
+			return Comparison{Result: r, Reason: ComparisonReasonResolution}, nil
+		}
+	}
+	if r := compareInts(sourceTypeRanks[a.SourceType], sourceTypeRanks[b.SourceType]); r != 0 {
+		return Comparison{Result: r, Reason: ComparisonReasonSourceType}, nil
+	}
+	if r := compareInts(bitDepth(a), bitDepth(b)); r != 0 {
+		return Comparison{Result: r, Reason: ComparisonReasonBitDepth}, nil
+	}
+	if r := compareBools(hasLosslessAudio(a), hasLosslessAudio(b)); r != 0 {
+		return Comparison{Result: r, Reason: ComparisonReasonLosslessAudio}, nil
+	}
+	return Comparison{}, nil
+}
+
+// SameEpisode returns true if both Elements describe the same episode of the same anime.
+// Titles are compared ignoring case, punctuation and spacing, and episode numbers ignoring leading zeros.
+func (e *Elements) SameEpisode(other *Elements) bool {
+	if normalizeTitle(e.AnimeTitle) != normalizeTitle(other.AnimeTitle) {
+		return false
+	}
+	if !equalNumbers(e.AnimeSeason, other.AnimeSeason) {
+		return false
+	}
+	return equalNumbers(e.EpisodeNumber, other.EpisodeNumber)
+}
+
+// normalizeTitle reduces a title to its lowercase letters and digits.
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func equalNumbers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimLeft(a[i], "0") != strings.TrimLeft(b[i], "0") {
+			return false
+		}
+	}
+	return true
+}
+
+// releaseVersion returns the highest release version of e. Releases without a version are the first version.
+func releaseVersion(e *Elements) int {
+	if len(e.ReleaseVersion) == 0 {
+		return 1
+	}
+	version := 0
+	for _, v := range e.ReleaseVersion {
+		n, err := strconv.Atoi(v)
+		if err == nil {
+			version = max(version, n)
+		}
+	}
+	return version
+}
+
+func revisionCounter(e *Elements) int {
+	if e.Revision == nil {
+		return 0
+	}
+	return e.Revision.Counter
+}
+
+func bitDepth(e *Elements) int {
+	if e.Video == nil {
+		return 0
+	}
+	return e.Video.BitDepth
+}
+
+func hasLosslessAudio(e *Elements) bool {
+	if e.Audio == nil {
+		return false
+	}
+	for _, t := range e.Audio.Tracks {
+		if t.Lossless {
+			return true
+		}
+	}
+	return false
+}
+
+func compareBools(a, b bool) int {
+	if a == b {
+		return 0
+	}
+	if a {
+		return 1
+	}
+	return -1
+}
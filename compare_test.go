@@ -0,0 +1,42 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestCompareCompareReleases(t *testing.T) {
+	testCases := []struct {
+		a, b   string
+		result int
+		reason ComparisonReason
+	}{
+		{"[Group] Title - 07v2 [720p].mkv", "[Group] Title - 07 [1080p].mkv", 1, ComparisonReasonReleaseVersion},
+		{"[Group] Title - 07v0 [1080p].mkv", "[Group] Title - 07 [1080p].mkv", -1, ComparisonReasonReleaseVersion},
+		{"Title.S01E07.1080p.WEB-DL.REPACK.mkv", "Title.S01E07.1080p.WEB-DL.REPACK2.mkv", -1, ComparisonReasonRevision},
+		{"Title.S01E07.1080p.WEB-DL.PROPER.mkv", "Title.S01E07.1080p.WEB-DL.NUKED.mkv", 1, ComparisonReasonRevision},
+		{"[A] Title - 07v2 [720p].mkv", "[B] Title - 07 [1080p].mkv", -1, ComparisonReasonResolution},
+		{"[A] Title - 07 [BD 1080p].mkv", "[B] Title - 07 [WEB 1080p].mkv", 1, ComparisonReasonSourceType},
+		{"[A] Title - 07 [1080p 10bit].mkv", "[B] Title - 07 [1080p 8bit].mkv", 1, ComparisonReasonBitDepth},
+		{"[A] Title - 07 [1080p AAC].mkv", "[B] Title - 07 [1080p FLAC].mkv", -1, ComparisonReasonLosslessAudio},
+		{"[A] Title - 07 [1080p].mkv", "[B] title - 007 [1080p].mkv", 0, ComparisonReasonNone},
+	}
+
+	for _, tc := range testCases {
+		c, err := CompareReleases(Parse(tc.a, DefaultOptions), Parse(tc.b, DefaultOptions))
+		if err != nil {
+			t.Errorf("%s vs %s: unexpected error %v", tc.a, tc.b, err)
+			continue
+		}
+		if c.Result != tc.result || c.Reason != tc.reason {
+			t.Errorf("%s vs %s: expected %d (%s), got %d (%s)", tc.a, tc.b, tc.result, tc.reason, c.Result, c.Reason)
+		}
+		if c.Supersedes() != (tc.result > 0) {
+			t.Errorf("%s vs %s: expected Supersedes to be %t", tc.a, tc.b, tc.result > 0)
+		}
+	}
+
+	_, err := CompareReleases(Parse("[A] Title - 07 [1080p].mkv", DefaultOptions), Parse("[A] Title - 08 [1080p].mkv", DefaultOptions))
+	if err != ErrDifferentEpisode {
+		t.Errorf("expected ErrDifferentEpisode, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package anitogo
+
+import "strings"
+
+// Censorship tells whether a release is censored. The zero value means the filename does not say.
+type Censorship string
+
+const (
+	// CensorshipUnknown is used when the filename does not say whether the release is censored.
+	CensorshipUnknown Censorship = ""
+
+	// CensorshipCensored is used for releases tagged "Censored".
+	CensorshipCensored Censorship = "censored"
+
+	// CensorshipUncensored is used for releases tagged "Uncensored" or "Decensored".
+	CensorshipUncensored Censorship = "uncensored"
+)
+
+var censorshipTable = map[string]Censorship{
+	"CENSORED": CensorshipCensored, "UNCENSORED": CensorshipUncensored, "DECENSORED": CensorshipUncensored,
+}
+
+// editionOtherTable maps the editions that are also kept in Other, as they were before editions were parsed,
+// to their canonical value.
+var editionOtherTable = map[string]string{
+	"REMASTER": "Remastered", "REMASTERED": "Remastered", "UNCUT": "Uncut",
+}
+
+// editionMaxWords is the number of words in the longest edition phrase, e.g "Extended Edition".
+const editionMaxWords = 2
+
+// searchForEdition identifies editions written as several words, e.g "Director's Cut" or "TV Edit".
+// Editions that are a single common word, e.g "Extended", are only accepted inside brackets.
+// It runs before the keyword search so the words are not claimed by other categories, e.g "TV" or "BD".
+func (p *parser) searchForEdition() {
+	tkns := *p.tokenizer.tokens
+	for i := 0; i < len(tkns); i++ {
+		if tkns[i].Category != tokenCategoryUnknown {
+			continue
+		}
+		for n := editionMaxWords; n > 0; n-- {
+			words, ok := p.editionWords(i, n)
+			if !ok {
+				continue
+			}
+			phrase := strings.Join(words, " ")
+			kd, found := p.tokenizer.keywordManager.find(p.tokenizer.keywordManager.normalize(phrase), elementCategoryEdition)
+			if !found || kd.options.searchable {
+				continue
+			}
+			if n == 1 && !tkns[i].Enclosed {
+				continue
+			}
+			p.tokenizer.elements.insert(elementCategoryEdition, phrase)
+			for j := i; j < i+n*2-1; j++ {
+				tkns[j].Category = tokenCategoryIdentifier
+			}
+			i += n*2 - 2
+			break
+		}
+	}
+}
+
+// editionWords returns the contents of n unknown tokens starting at index that are separated by single delimiters.
+func (p *parser) editionWords(index, n int) ([]string, bool) {
+	tkns := *p.tokenizer.tokens
+	var words []string
+	for i := index; len(words) < n; i += 2 {
+		if i >= len(tkns) || tkns[i].Category != tokenCategoryUnknown {
+			return nil, false
+		}
+		if len(words) > 0 && tkns[i-1].Category != tokenCategoryDelimiter {
+			return nil, false
+		}
+		words = append(words, tkns[i].Content)
+	}
+	return words, true
+}
+
+// buildEdition copies the editions found in Other, e.g "Remastered", to Edition.
+func (p *parser) buildEdition() {
+	e := p.tokenizer.elements
+	for _, other := range e.Other {
+		if _, found := editionOtherTable[p.tokenizer.keywordManager.normalize(other)]; found {
+			e.insert(elementCategoryEdition, other)
+		}
+	}
+}
+
+func (p *parser) buildCensorship() {
+	e := p.tokenizer.elements
+	for _, other := range e.Other {
+		if c, found := censorshipTable[p.tokenizer.keywordManager.normalize(other)]; found {
+			e.Censorship = c
+			return
+		}
+	}
+}
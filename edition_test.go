@@ -0,0 +1,59 @@
+package anitogo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditionSearchForEdition(t *testing.T) {
+	e := Parse("[chibi-Doki] Seikon no Qwaser - 13v0 (Uncensored Director's Cut) [988DB090].mkv", DefaultOptions)
+	if !reflect.DeepEqual(e.Edition, []string{"Director's Cut"}) {
+		t.Errorf("expected [Director's Cut], got %v", e.Edition)
+	}
+	if e.Censorship != CensorshipUncensored {
+		t.Errorf("expected %q, got %q", CensorshipUncensored, e.Censorship)
+	}
+
+	e = Parse("[Group] Title - 05 [TV Edit][BD Edit][Extended] [1080p].mkv", DefaultOptions)
+	if !reflect.DeepEqual(e.Edition, []string{"TV Edit", "BD Edit", "Extended"}) {
+		t.Errorf("expected [TV Edit BD Edit Extended], got %v", e.Edition)
+	}
+	if len(e.Source) != 0 || e.AnimeType != nil {
+		t.Errorf("expected no source or type, got %v and %v", e.Source, e.AnimeType)
+	}
+	if !reflect.DeepEqual(e.Canonical["edition"], []string{"TV Edit", "BD Edit", "Extended"}) {
+		t.Errorf("expected canonical [TV Edit BD Edit Extended], got %v", e.Canonical["edition"])
+	}
+
+	e = Parse("Title.S01E05.Theatrical.Cut.1080p.mkv", DefaultOptions)
+	if !reflect.DeepEqual(e.Canonical["edition"], []string{"Theatrical"}) {
+		t.Errorf("expected canonical [Theatrical], got %v", e.Canonical["edition"])
+	}
+
+	e = Parse("[Group] Extended Title - 01 [1080p][Remastered][Censored].mkv", DefaultOptions)
+	if e.AnimeTitle != "Extended Title" {
+		t.Errorf("expected \"Extended Title\", got \"%s\"", e.AnimeTitle)
+	}
+	if !reflect.DeepEqual(e.Edition, []string{"Remastered"}) {
+		t.Errorf("expected [Remastered], got %v", e.Edition)
+	}
+	if !reflect.DeepEqual(e.Other, []string{"Remastered", "Censored"}) {
+		t.Errorf("expected Remastered to stay in Other, got %v", e.Other)
+	}
+	if e.Censorship != CensorshipCensored {
+		t.Errorf("expected %q, got %q", CensorshipCensored, e.Censorship)
+	}
+
+	e = Parse("[Group] Title - 01 [BD 1080p][UNCUT][REMASTER].mkv", DefaultOptions)
+	if !reflect.DeepEqual(e.Canonical["edition"], []string{"Uncut", "Remastered"}) {
+		t.Errorf("expected canonical [Uncut Remastered], got %v", e.Canonical["edition"])
+	}
+	if !reflect.DeepEqual(e.Canonical["other"], []string{"Uncut", "Remastered"}) {
+		t.Errorf("expected canonical [Uncut Remastered], got %v", e.Canonical["other"])
+	}
+
+	e = Parse("[Group] Title - 01 [1080p].mkv", DefaultOptions)
+	if e.Edition != nil || e.Censorship != CensorshipUnknown {
+		t.Errorf("expected no edition or censorship, got %v and %q", e.Edition, e.Censorship)
+	}
+}
@@ -23,9 +23,20 @@ type Elements struct {
 	// Slice of strings representing the audio terms included in the filename, e.g FLAC, AAC, etc.
 	AudioTerm []string `json:"audio_term,omitempty"`
 
+	// Audio tracks described by the audio terms, with the codec, channel layout and track count normalised,
+	// e.g "DDP5.1" is parsed into the E-AC-3 codec with 5.1 channels.
+	Audio *Audio `json:"audio,omitempty"`
+
+	// Episodes and seasons covered when the name is a batch or season pack rather than a single file,
+	// e.g "[Group] Title (01-24) [BD 1080p]" covers 24 episodes. Nil for single files.
+	Batch *Batch `json:"batch,omitempty"`
+
 	// Slice of strings representing devices the video is compatible with that are mentioned in the filename.
 	DeviceCompatibility []string `json:"device_compatibility,omitempty"`
 
+	// Slice of strings representing the edition or cut of the release, e.g "Director's Cut", "TV Edit" or "Uncut".
+	Edition []string `json:"edition,omitempty"`
+
 	// Slice of strings representing the episode numbers. "01-10" would be respresented as []string{"1", "10"}.
 	EpisodeNumber []string `json:"episode_number,omitempty"`
 
@@ -57,6 +68,13 @@ type Elements struct {
 	// Languages specified in the file name, e.g RU, JP, EN etc.
 	Language []string `json:"language,omitempty"`
 
+	// Languages specified in the file name normalised to their ISO 639 codes, including languages
+	// combined into tags like "[ENG+JPN+CHI]" and whether they apply to the audio or the subtitles.
+	Languages []Language `json:"languages,omitempty"`
+
+	// Whether the release is censored, uncensored or does not say, e.g "Uncensored" is CensorshipUncensored.
+	Censorship Censorship `json:"censorship,omitempty"`
+
 	// Terms that could not be parsed into other buckets, but were deemed identifiers.
 	// In [chibi-Doki] Seikon no Qwaser - 13v0 (Uncensored Director's Cut) [988DB090].mkv,
 	// "Uncensored" is parsed into Other.
@@ -75,9 +93,21 @@ type Elements struct {
 	// In [FBI] Baby Princess 3D Paradise Love 01v0 [BD][720p-AAC][457CC066].mkv, 0 is parsed into ReleaseVersion.
 	ReleaseVersion []string `json:"release_version,omitempty"`
 
+	// Scene revision markers found in the filename, e.g PROPER, REPACK2 or INTERNAL,
+	// with a counter of how many times the video was revised.
+	Revision *Revision `json:"revision,omitempty"`
+
 	// Slice of strings representing where the video was ripped from. e.g BLU-RAY, DVD, etc.
 	Source []string `json:"source,omitempty"`
 
+	// Type of the first source normalised to one of the SourceType constants,
+	// e.g "BLU-RAY" and "BDRip" are SourceTypeBD and "WEBRip" is SourceTypeWEBRip.
+	SourceType SourceType `json:"source_type,omitempty"`
+
+	// Streaming service the video was ripped from. In [Group] Title - 01 [1080p CR WEB-DL],
+	// "CR" is the StreamingService, and its full name "Crunchyroll" is the canonical value.
+	StreamingService string `json:"streaming_service,omitempty"`
+
 	// Slice of strings representing the type of subtitles included, e.g HARDSUB, BIG5, etc.
 	Subtitles []string `json:"subtitles,omitempty"`
 
@@ -85,9 +115,17 @@ type Elements struct {
 	// on how it is represented in the filename.
 	VideoResolution string `json:"video_resolution,omitempty"`
 
+	// Resolution of the video parsed into its width and height with a canonical label,
+	// e.g "1920x1080", "1080P" and "FHD" are all labelled "1080p".
+	Resolution *Resolution `json:"resolution,omitempty"`
+
 	// Slice of strings representing the video terms included in the filename, e.g h264, x264, etc.
 	VideoTerm []string `json:"video_term,omitempty"`
 
+	// Video stream described by the video terms, with the codec, encoder, bit depth, HDR formats and frame rate
+	// normalised, e.g "x264" and "Hi10P" are parsed into the H.264 codec, the x264 encoder and a bit depth of 10.
+	Video *Video `json:"video,omitempty"`
+
 	// Slice of strings represnting the volume numbers. "01-10" would be represented as []string{"1", "10"}.
 	VolumeNumber []string `json:"volume_number,omitempty"`
 
@@ -97,6 +135,11 @@ type Elements struct {
 	// Entries that could not be parsed into any other categories.
 	Unknown []string `json:"unknown,omitempty"`
 
+	// Canonical values of the keywords found in the filename, keyed by the JSON name of their field.
+	// e.g "BD", "BLURAY" and "Blu-Ray" in "source" are all represented as "Blu-ray",
+	// and "x264", "AVC" and "H.264" in "video_term" are all represented as "H.264".
+	Canonical map[string][]string `json:"canonical,omitempty"`
+
 	// Bool determining if "EpisodeNumberAlt" should be parsed or not.
 	checkAltNumber bool
 }
@@ -109,6 +152,7 @@ const (
 	elementCategoryAnimeYear
 	elementCategoryAudioTerm
 	elementCategoryDeviceCompatibility
+	elementCategoryEdition
 	elementCategoryEpisodeNumber
 	elementCategoryEpisodeNumberAlt
 	elementCategoryEpisodePrefix
@@ -122,6 +166,7 @@ const (
 	elementCategoryReleaseInformation
 	elementCategoryReleaseVersion
 	elementCategorySource
+	elementCategoryStreamingService
 	elementCategorySubtitles
 	elementCategoryVideoResolution
 	elementCategoryVideoTerm
@@ -130,6 +175,37 @@ const (
 	elementCategoryUnknown
 )
 
+var elementCategoryNames = map[elementCategory]string{
+	elementCategoryAnimeSeason:         "anime_season",
+	elementCategoryAnimeSeasonPrefix:   "anime_season_prefix",
+	elementCategoryAnimeTitle:          "anime_title",
+	elementCategoryAnimeType:           "anime_type",
+	elementCategoryAnimeYear:           "anime_year",
+	elementCategoryAudioTerm:           "audio_term",
+	elementCategoryDeviceCompatibility: "device_compatibility",
+	elementCategoryEdition:             "edition",
+	elementCategoryEpisodeNumber:       "episode_number",
+	elementCategoryEpisodeNumberAlt:    "episode_number_alt",
+	elementCategoryEpisodePrefix:       "episode_prefix",
+	elementCategoryEpisodeTitle:        "episode_title",
+	elementCategoryFileChecksum:        "file_checksum",
+	elementCategoryFileExtension:       "file_extension",
+	elementCategoryFileName:            "file_name",
+	elementCategoryLanguage:            "language",
+	elementCategoryOther:               "other",
+	elementCategoryReleaseGroup:        "release_group",
+	elementCategoryReleaseInformation:  "release_information",
+	elementCategoryReleaseVersion:      "release_version",
+	elementCategorySource:              "source",
+	elementCategoryStreamingService:    "streaming_service",
+	elementCategorySubtitles:           "subtitles",
+	elementCategoryVideoResolution:     "video_resolution",
+	elementCategoryVideoTerm:           "video_term",
+	elementCategoryVolumeNumber:        "volume_number",
+	elementCategoryVolumePrefix:        "volume_prefix",
+	elementCategoryUnknown:             "unknown",
+}
+
 func (e *Elements) getCheckAltNumber() bool {
 	return e.checkAltNumber
 }
@@ -154,6 +230,8 @@ func (e *Elements) getSingleElementField(cat elementCategory) (bool, *string) {
 		return true, &e.FileName
 	case elementCategoryReleaseGroup:
 		return true, &e.ReleaseGroup
+	case elementCategoryStreamingService:
+		return true, &e.StreamingService
 	case elementCategoryVideoResolution:
 		return true, &e.VideoResolution
 	}
@@ -173,6 +251,8 @@ func (e *Elements) getMultiElementField(cat elementCategory) (bool, *[]string) {
 		return true, &e.AudioTerm
 	case elementCategoryDeviceCompatibility:
 		return true, &e.DeviceCompatibility
+	case elementCategoryEdition:
+		return true, &e.Edition
 	case elementCategoryEpisodeNumber:
 		return true, &e.EpisodeNumber
 	case elementCategoryEpisodeNumberAlt:
@@ -273,6 +353,7 @@ func (e elementCategory) isSearchable() bool {
 		elementCategoryAnimeType,
 		elementCategoryAudioTerm,
 		elementCategoryDeviceCompatibility,
+		elementCategoryEdition,
 		elementCategoryEpisodePrefix,
 		elementCategoryFileChecksum,
 		elementCategoryLanguage,
@@ -281,6 +362,7 @@ func (e elementCategory) isSearchable() bool {
 		elementCategoryReleaseInformation,
 		elementCategoryReleaseVersion,
 		elementCategorySource,
+		elementCategoryStreamingService,
 		elementCategorySubtitles,
 		elementCategoryVideoResolution,
 		elementCategoryVideoTerm,
@@ -301,6 +383,7 @@ func (e elementCategory) isSingular() bool {
 		elementCategoryAnimeType,
 		elementCategoryAudioTerm,
 		elementCategoryDeviceCompatibility,
+		elementCategoryEdition,
 		elementCategoryEpisodeNumber,
 		elementCategoryLanguage,
 		elementCategoryOther,
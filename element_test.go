@@ -10,6 +10,7 @@ var multiElementFields = []elementCategory{
 	elementCategoryAnimeType,
 	elementCategoryAudioTerm,
 	elementCategoryDeviceCompatibility,
+	elementCategoryEdition,
 	elementCategoryEpisodeNumber,
 	elementCategoryEpisodeNumberAlt,
 	elementCategoryEpisodePrefix,
@@ -33,6 +34,7 @@ var singleElementFields = []elementCategory{
 	elementCategoryFileExtension,
 	elementCategoryFileName,
 	elementCategoryReleaseGroup,
+	elementCategoryStreamingService,
 	elementCategoryVideoResolution,
 }
 
@@ -0,0 +1,283 @@
+// Package feed reads RSS and Atom feeds of torrent trackers and parses the title of every item with anitogo.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// DefaultOptions is a variable configured with the recommended defaults for the Options struct to be passed to Read.
+var DefaultOptions = Options{
+	Parser: anitogo.DefaultOptions,
+}
+
+// Options configures how a feed is read.
+type Options struct {
+	// DefaultOptions value: anitogo.DefaultOptions
+	// Options used to parse the title of every item.
+	Parser anitogo.Options
+
+	// DefaultOptions value: nil
+	// If set, only the items whose parsed title matches the filter are returned.
+	Filter *anitogo.Filter
+}
+
+// Feed is an RSS or Atom feed.
+type Feed struct {
+	// Title of the feed.
+	Title string `json:"title,omitempty"`
+
+	// Items of the feed in the order they appear, after the filter was applied.
+	Items []Item `json:"items"`
+}
+
+// Item is a single release of a feed.
+type Item struct {
+	// Title of the item as it appears in the feed.
+	Title string `json:"title"`
+
+	// Link to the torrent file or magnet link of the item.
+	Link string `json:"link,omitempty"`
+
+	// Unique identifier of the item, usually the URL of its page on the tracker.
+	GUID string `json:"guid,omitempty"`
+
+	// Time the item was published. Zero if the feed does not say.
+	Published time.Time `json:"published,omitempty"`
+
+	// BitTorrent info hash of the item, in lowercase hex.
+	InfoHash string `json:"info_hash,omitempty"`
+
+	// Size of the item in bytes. Zero if the feed does not say.
+	Size int64 `json:"size,omitempty"`
+
+	// Name of the tracker category, e.g "Anime - English-translated".
+	Category string `json:"category,omitempty"`
+
+	// Identifier of the tracker category, e.g "1_2".
+	CategoryID string `json:"category_id,omitempty"`
+
+	// Number of seeders. Zero if the feed does not say.
+	Seeders int `json:"seeders,omitempty"`
+
+	// Number of leechers. Zero if the feed does not say.
+	Leechers int `json:"leechers,omitempty"`
+
+	// Elements parsed from the title.
+	Elements *anitogo.Elements `json:"elements"`
+}
+
+// document holds the elements of RSS 2.0, RSS 1.0 and Atom feeds. The root element tells which one was read.
+type document struct {
+	XMLName xml.Name
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Title   string      `xml:"title"`
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title      string   `xml:"title"`
+	Link       string   `xml:"link"`
+	GUID       string   `xml:"guid"`
+	PubDate    string   `xml:"pubDate"`
+	Date       string   `xml:"date"`
+	Categories []string `xml:"category"`
+	CategoryID string   `xml:"categoryId"`
+	InfoHash   string   `xml:"infoHash"`
+	Size       string   `xml:"size"`
+	Seeders    string   `xml:"seeders"`
+	Leechers   string   `xml:"leechers"`
+	Enclosure  struct {
+		URL    string `xml:"url,attr"`
+		Length string `xml:"length,attr"`
+	} `xml:"enclosure"`
+	// Attributes of Torznab and Newznab feeds, e.g <torznab:attr name="infohash" value="..."/>.
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	ID    string `xml:"id"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Published  string `xml:"published"`
+	Updated    string `xml:"updated"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+// Read reads an RSS or Atom feed from r and parses the title of every item.
+func Read(r io.Reader, options Options) (*Feed, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("feed: invalid XML: %w", err)
+	}
+
+	f := &Feed{Items: []Item{}}
+	var items []Item
+	switch doc.XMLName.Local {
+	case "rss":
+		f.Title = strings.TrimSpace(doc.Channel.Title)
+		items = convertRSSItems(doc.Channel.Items)
+	case "RDF":
+		f.Title = strings.TrimSpace(doc.Channel.Title)
+		items = convertRSSItems(doc.Items)
+	case "feed":
+		f.Title = strings.TrimSpace(doc.Title)
+		items = convertAtomEntries(doc.Entries)
+	default:
+		return nil, fmt.Errorf("feed: unknown root element <%s>, expected <rss>, <rdf:RDF> or <feed>", doc.XMLName.Local)
+	}
+
+	for _, item := range items {
+		item.Elements = anitogo.Parse(item.Title, options.Parser)
+		if options.Filter != nil && !options.Filter.Match(item.Elements) {
+			continue
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+func convertRSSItems(rssItems []rssItem) []Item {
+	var items []Item
+	for _, ri := range rssItems {
+		item := Item{
+			Title:      strings.TrimSpace(ri.Title),
+			Link:       strings.TrimSpace(ri.Link),
+			GUID:       strings.TrimSpace(ri.GUID),
+			Published:  parseTime(ri.PubDate, ri.Date),
+			InfoHash:   strings.ToLower(strings.TrimSpace(ri.InfoHash)),
+			Size:       parseSize(ri.Size),
+			CategoryID: strings.TrimSpace(ri.CategoryID),
+			Seeders:    parseInt(ri.Seeders),
+			Leechers:   parseInt(ri.Leechers),
+		}
+		for _, c := range ri.Categories {
+			if c = strings.TrimSpace(c); c != "" {
+				item.Category = c
+				break
+			}
+		}
+		if item.Link == "" {
+			item.Link = ri.Enclosure.URL
+		}
+		if item.Size == 0 {
+			item.Size = parseSize(ri.Enclosure.Length)
+		}
+		for _, attr := range ri.Attrs {
+			switch strings.ToLower(attr.Name) {
+			case "infohash":
+				if item.InfoHash == "" {
+					item.InfoHash = strings.ToLower(attr.Value)
+				}
+			case "size":
+				if item.Size == 0 {
+					item.Size = parseSize(attr.Value)
+				}
+			case "seeders":
+				if item.Seeders == 0 {
+					item.Seeders = parseInt(attr.Value)
+				}
+			case "leechers":
+				if item.Leechers == 0 {
+					item.Leechers = parseInt(attr.Value)
+				}
+			case "category":
+				if item.CategoryID == "" {
+					item.CategoryID = attr.Value
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func convertAtomEntries(entries []atomEntry) []Item {
+	var items []Item
+	for _, entry := range entries {
+		item := Item{
+			Title:     strings.TrimSpace(entry.Title),
+			GUID:      strings.TrimSpace(entry.ID),
+			Published: parseTime(entry.Published, entry.Updated),
+		}
+		for _, l := range entry.Links {
+			// Prefer the torrent file over the page of the item.
+			if l.Rel == "enclosure" || l.Type == "application/x-bittorrent" || item.Link == "" {
+				item.Link = l.Href
+			}
+		}
+		if len(entry.Categories) > 0 {
+			item.Category = entry.Categories[0].Label
+			item.CategoryID = entry.Categories[0].Term
+			if item.Category == "" {
+				item.Category = item.CategoryID
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+var timeLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "2006-01-02 15:04:05"}
+
+// parseTime parses the first of the values that is a valid date.
+func parseTime(values ...string) time.Time {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+var sizeUnits = map[string]float64{
+	"": 1, "B": 1,
+	"KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12,
+	"KIB": 1 << 10, "MIB": 1 << 20, "GIB": 1 << 30, "TIB": 1 << 40,
+}
+
+// parseSize parses a size in bytes, e.g "1073741824", or with a unit, e.g "1.4 GiB".
+func parseSize(str string) int64 {
+	str = strings.TrimSpace(str)
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	number, unit := str, ""
+	if i != -1 {
+		number, unit = str[:i], strings.ToUpper(strings.TrimSpace(str[i:]))
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	multiplier, found := sizeUnits[unit]
+	if err != nil || !found {
+		return 0
+	}
+	return int64(n * multiplier)
+}
+
+func parseInt(str string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(str))
+	return n
+}
@@ -0,0 +1,126 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+const testNyaaFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss xmlns:atom="http://www.w3.org/2005/Atom" xmlns:nyaa="https://nyaa.si/xmlns/nyaa" version="2.0">
+	<channel>
+		<title>Nyaa - Home - Torrent File RSS</title>
+		<item>
+			<title>[SubsPlease] Sousou no Frieren - 05 (1080p) [A1B2C3D4].mkv</title>
+			<link>https://nyaa.si/download/1.torrent</link>
+			<guid isPermaLink="true">https://nyaa.si/view/1</guid>
+			<pubDate>Fri, 06 Oct 2023 15:01:23 -0000</pubDate>
+			<nyaa:seeders>1024</nyaa:seeders>
+			<nyaa:leechers>12</nyaa:leechers>
+			<nyaa:infoHash>0123456789ABCDEF0123456789ABCDEF01234567</nyaa:infoHash>
+			<nyaa:categoryId>1_2</nyaa:categoryId>
+			<nyaa:category>Anime - English-translated</nyaa:category>
+			<nyaa:size>1.4 GiB</nyaa:size>
+		</item>
+		<item>
+			<title>[Erai-raws] Sousou no Frieren - 05 [720p][HARDSUB].mkv</title>
+			<link>https://nyaa.si/download/2.torrent</link>
+			<nyaa:size>350.2 MiB</nyaa:size>
+		</item>
+	</channel>
+</rss>`
+
+const testTorznabFeed = `<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+	<channel>
+		<item>
+			<title>[Group] Title - 01 [1080p].mkv</title>
+			<enclosure url="https://example.com/1.torrent" length="734003200" type="application/x-bittorrent"/>
+			<torznab:attr name="infohash" value="ABCDEF0123456789ABCDEF0123456789ABCDEF01"/>
+			<torznab:attr name="seeders" value="5"/>
+			<torznab:attr name="category" value="5070"/>
+		</item>
+	</channel>
+</rss>`
+
+const testAtomFeed = `<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Releases</title>
+	<entry>
+		<title>[Group] Title - 02 [1080p].mkv</title>
+		<id>urn:release:2</id>
+		<updated>2023-10-06T15:01:23Z</updated>
+		<link rel="alternate" href="https://example.com/view/2"/>
+		<link rel="enclosure" type="application/x-bittorrent" href="https://example.com/2.torrent"/>
+		<category term="anime" label="Anime"/>
+	</entry>
+</feed>`
+
+func TestFeedRead(t *testing.T) {
+	f, err := Read(strings.NewReader(testNyaaFeed), DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if f.Title != "Nyaa - Home - Torrent File RSS" || len(f.Items) != 2 {
+		t.Fatalf("expected 2 items, got %v", f)
+	}
+	item := f.Items[0]
+	if item.InfoHash != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("expected a lowercase info hash, got %s", item.InfoHash)
+	}
+	if item.Size != 1503238553 || item.Seeders != 1024 || item.Leechers != 12 {
+		t.Errorf("expected 1.4 GiB with 1024 seeders and 12 leechers, got %d, %d and %d", item.Size, item.Seeders, item.Leechers)
+	}
+	if item.Category != "Anime - English-translated" || item.CategoryID != "1_2" {
+		t.Errorf("expected category 1_2, got %s (%s)", item.CategoryID, item.Category)
+	}
+	if !item.Published.Equal(time.Date(2023, 10, 6, 15, 1, 23, 0, time.UTC)) {
+		t.Errorf("expected 2023-10-06 15:01:23, got %v", item.Published)
+	}
+	if item.Elements.AnimeTitle != "Sousou no Frieren" || item.Elements.ReleaseGroup != "SubsPlease" {
+		t.Errorf("expected Sousou no Frieren by SubsPlease, got %v", item.Elements)
+	}
+
+	options := DefaultOptions
+	options.Filter = anitogo.MustCompileFilter(`resolution >= 1080 and not "HARDSUB" in subtitles`)
+	f, err = Read(strings.NewReader(testNyaaFeed), options)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(f.Items) != 1 || f.Items[0].Elements.ReleaseGroup != "SubsPlease" {
+		t.Errorf("expected the SubsPlease item, got %v", f.Items)
+	}
+
+	f, err = Read(strings.NewReader(testTorznabFeed), DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	item = f.Items[0]
+	if item.Link != "https://example.com/1.torrent" || item.Size != 734003200 || item.Seeders != 5 || item.CategoryID != "5070" {
+		t.Errorf("expected the enclosure and torznab attributes, got %v", item)
+	}
+	if item.InfoHash != "abcdef0123456789abcdef0123456789abcdef01" {
+		t.Errorf("expected a lowercase info hash, got %s", item.InfoHash)
+	}
+
+	f, err = Read(strings.NewReader(testAtomFeed), DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if f.Title != "Releases" || len(f.Items) != 1 {
+		t.Fatalf("expected 1 item, got %v", f)
+	}
+	item = f.Items[0]
+	if item.Link != "https://example.com/2.torrent" || item.GUID != "urn:release:2" || item.Category != "Anime" {
+		t.Errorf("expected the enclosure link, id and category, got %v", item)
+	}
+	if len(item.Elements.EpisodeNumber) != 1 || item.Elements.EpisodeNumber[0] != "02" {
+		t.Errorf("expected episode 02, got %v", item.Elements.EpisodeNumber)
+	}
+
+	for _, data := range []string{"<rss><channel>", "<html></html>"} {
+		if _, err := Read(strings.NewReader(data), DefaultOptions); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
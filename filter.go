@@ -0,0 +1,567 @@
+package anitogo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is a compiled filter expression that can be matched against Elements.
+//
+// Expressions compare fields, named after the JSON tags of Elements, with literals or lists:
+//
+//	resolution >= 1080 and release_group in ("SubsPlease", "Erai-raws") and not "HARDSUB" in subtitles
+//	anime_title =~ "(?i)^one piece" and episode_number > 1000
+//
+// The supported operators are ==, !=, <, <=, >, >=, =~ (regular expression match), !~, in, and, or and not.
+// A field on its own is true when it has a value. Fields holding several values, e.g episode_number,
+// match when any of their values does. Comparisons with a number are numeric, e.g "01" equals 1,
+// and string comparisons ignore case.
+//
+// Besides the JSON tags, the following fields are available: resolution (the height of the video),
+// group, title, episode and season (aliases of release_group, anime_title, episode_number and anime_season),
+// source_type, censorship, bit_depth and revision (the scene revision counter).
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+// FilterError is returned by CompileFilter when an expression is invalid.
+type FilterError struct {
+	// Expression that failed to compile.
+	Expr string
+
+	// Byte offset in Expr where the error was found.
+	Pos int
+
+	// Description of the error.
+	Msg string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("anitogo: invalid filter %q at position %d: %s", e.Expr, e.Pos, e.Msg)
+}
+
+// CompileFilter compiles a filter expression. The returned error is a *FilterError.
+func CompileFilter(expr string) (*Filter, error) {
+	tkns, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	fp := &filterParser{expr: expr, tokens: tkns}
+	root, err := fp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := fp.peek(); t.kind != filterTokenEnd {
+		return nil, fp.errorf(t, "unexpected %s", t)
+	}
+	return &Filter{expr: expr, root: root}, nil
+}
+
+// MustCompileFilter is like CompileFilter but panics if the expression is invalid.
+func MustCompileFilter(expr string) *Filter {
+	f, err := CompileFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Match returns true if e satisfies the filter.
+func (f *Filter) Match(e *Elements) bool {
+	return f.root.match(e)
+}
+
+func (f *Filter) String() string {
+	return f.expr
+}
+
+var filterFieldAliases = map[string]string{
+	"group":   "release_group",
+	"title":   "anime_title",
+	"episode": "episode_number",
+	"season":  "anime_season",
+}
+
+var filterStructuredFields = map[string]func(*Elements) []string{
+	"resolution": func(e *Elements) []string {
+		if e.Resolution == nil {
+			return nil
+		}
+		return []string{strconv.Itoa(e.Resolution.Height)}
+	},
+	"source_type": func(e *Elements) []string {
+		return nonEmptyValues(string(e.SourceType))
+	},
+	"censorship": func(e *Elements) []string {
+		return nonEmptyValues(string(e.Censorship))
+	},
+	"bit_depth": func(e *Elements) []string {
+		if e.Video == nil || e.Video.BitDepth == 0 {
+			return nil
+		}
+		return []string{strconv.Itoa(e.Video.BitDepth)}
+	},
+	"revision": func(e *Elements) []string {
+		if e.Revision == nil {
+			return nil
+		}
+		return []string{strconv.Itoa(e.Revision.Counter)}
+	},
+}
+
+// filterField returns the function reading the values of a field, or nil if the field does not exist.
+func filterField(name string) func(*Elements) []string {
+	if alias, found := filterFieldAliases[name]; found {
+		name = alias
+	}
+	if fn, found := filterStructuredFields[name]; found {
+		return fn
+	}
+	for cat, n := range elementCategoryNames {
+		if n == name && cat != elementCategoryUnknown {
+			cat := cat
+			return func(e *Elements) []string {
+				return nonEmptyValues(e.get(cat)...)
+			}
+		}
+	}
+	return nil
+}
+
+func nonEmptyValues(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+type filterNode interface {
+	match(e *Elements) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+
+func (n filterAnd) match(e *Elements) bool { return n.left.match(e) && n.right.match(e) }
+
+type filterOr struct{ left, right filterNode }
+
+func (n filterOr) match(e *Elements) bool { return n.left.match(e) || n.right.match(e) }
+
+type filterNot struct{ node filterNode }
+
+func (n filterNot) match(e *Elements) bool { return !n.node.match(e) }
+
+// filterOperand is a field or a list of literals.
+type filterOperand struct {
+	field  func(*Elements) []string
+	values []string
+}
+
+func (o filterOperand) get(e *Elements) []string {
+	if o.field != nil {
+		return o.field(e)
+	}
+	return o.values
+}
+
+type filterExists struct{ operand filterOperand }
+
+func (n filterExists) match(e *Elements) bool { return len(n.operand.get(e)) > 0 }
+
+type filterCompare struct {
+	op          string
+	left, right filterOperand
+	numeric     bool
+}
+
+func (n filterCompare) match(e *Elements) bool {
+	for _, l := range n.left.get(e) {
+		for _, r := range n.right.get(e) {
+			if n.compare(l, r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n filterCompare) compare(l, r string) bool {
+	var c int
+	if n.numeric {
+		lf, err := strconv.ParseFloat(l, 64)
+		if err != nil {
+			return false
+		}
+		rf, _ := strconv.ParseFloat(r, 64)
+		switch {
+		case lf < rf:
+			c = -1
+		case lf > rf:
+			c = 1
+		}
+	} else {
+		c = strings.Compare(strings.ToLower(l), strings.ToLower(r))
+	}
+	switch n.op {
+	case "==", "in":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+type filterRegexp struct {
+	operand filterOperand
+	re      *regexp.Regexp
+	negate  bool
+}
+
+func (n filterRegexp) match(e *Elements) bool {
+	for _, v := range n.operand.get(e) {
+		if n.re.MatchString(v) {
+			return !n.negate
+		}
+	}
+	return n.negate
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEnd filterTokenKind = iota
+	filterTokenIdent
+	filterTokenString
+	filterTokenNumber
+	filterTokenOperator
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenComma
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+	pos   int
+}
+
+func (t filterToken) String() string {
+	switch t.kind {
+	case filterTokenEnd:
+		return "end of expression"
+	case filterTokenString:
+		return strconv.Quote(t.value)
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// keyword returns true if the token is the given keyword, ignoring case.
+func (t filterToken) keyword(k string) bool {
+	return t.kind == filterTokenIdent && strings.EqualFold(t.value, k)
+}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var tkns []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tkns = append(tkns, filterToken{filterTokenLParen, "(", i})
+			i++
+		case c == ')':
+			tkns = append(tkns, filterToken{filterTokenRParen, ")", i})
+			i++
+		case c == ',':
+			tkns = append(tkns, filterToken{filterTokenComma, ",", i})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(expr) && expr[end] != c {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, &FilterError{Expr: expr, Pos: i, Msg: "unterminated string"}
+			}
+			value, err := unquoteFilterString(expr[i+1:end], c)
+			if err != nil {
+				return nil, &FilterError{Expr: expr, Pos: i, Msg: "invalid string: " + err.Error()}
+			}
+			tkns = append(tkns, filterToken{filterTokenString, value, i})
+			i = end + 1
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(expr) && (isDigit(expr[end]) || expr[end] == '.') {
+				end++
+			}
+			if _, err := strconv.ParseFloat(expr[i:end], 64); err != nil {
+				return nil, &FilterError{Expr: expr, Pos: i, Msg: fmt.Sprintf("invalid number %q", expr[i:end])}
+			}
+			tkns = append(tkns, filterToken{filterTokenNumber, expr[i:end], i})
+			i = end
+		case c == '_' || isFilterLetter(expr[i:]):
+			end := i
+			for end < len(expr) && (expr[end] == '_' || isDigit(expr[end]) || isFilterLetter(expr[end:])) {
+				_, size := utf8.DecodeRuneInString(expr[end:])
+				end += size
+			}
+			tkns = append(tkns, filterToken{filterTokenIdent, expr[i:end], i})
+			i = end
+		case strings.ContainsRune("=!<>", rune(c)):
+			op := string(c)
+			if i+1 < len(expr) && (expr[i+1] == '=' || (expr[i+1] == '~' && c != '<' && c != '>')) {
+				op += string(expr[i+1])
+			}
+			if op == "=" || op == "!" {
+				return nil, &FilterError{Expr: expr, Pos: i, Msg: fmt.Sprintf("unknown operator %q, did you mean \"%s=\"?", op, op)}
+			}
+			tkns = append(tkns, filterToken{filterTokenOperator, op, i})
+			i += len(op)
+		default:
+			r, _ := utf8.DecodeRuneInString(expr[i:])
+			return nil, &FilterError{Expr: expr, Pos: i, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tkns, filterToken{kind: filterTokenEnd, pos: len(expr)}), nil
+}
+
+func unquoteFilterString(s string, quote byte) (string, error) {
+	if quote == '\'' {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, "\\'", "'"), "\"", "\\\"")
+	}
+	return strconv.Unquote("\"" + s + "\"")
+}
+
+// isFilterLetter returns true if s starts with a letter, which may take several bytes in UTF-8.
+func isFilterLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type filterParser struct {
+	expr   string
+	tokens []filterToken
+	pos    int
+}
+
+func (fp *filterParser) peek() filterToken {
+	return fp.tokens[fp.pos]
+}
+
+func (fp *filterParser) next() filterToken {
+	t := fp.tokens[fp.pos]
+	if t.kind != filterTokenEnd {
+		fp.pos++
+	}
+	return t
+}
+
+func (fp *filterParser) errorf(t filterToken, format string, args ...interface{}) error {
+	return &FilterError{Expr: fp.expr, Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (fp *filterParser) parseOr() (filterNode, error) {
+	left, err := fp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for fp.peek().keyword("or") {
+		fp.next()
+		right, err := fp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left, right}
+	}
+	return left, nil
+}
+
+func (fp *filterParser) parseAnd() (filterNode, error) {
+	left, err := fp.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for fp.peek().keyword("and") {
+		fp.next()
+		right, err := fp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left, right}
+	}
+	return left, nil
+}
+
+func (fp *filterParser) parseNot() (filterNode, error) {
+	if fp.peek().keyword("not") {
+		fp.next()
+		node, err := fp.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node}, nil
+	}
+	return fp.parsePrimary()
+}
+
+func (fp *filterParser) parsePrimary() (filterNode, error) {
+	if fp.peek().kind == filterTokenLParen {
+		open := fp.next()
+		node, err := fp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := fp.next(); t.kind != filterTokenRParen {
+			return nil, fp.errorf(t, "expected \")\" to close the parenthesis at position %d, got %s", open.pos, t)
+		}
+		return node, nil
+	}
+
+	leftToken := fp.peek()
+	left, err := fp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	opToken := fp.peek()
+	switch {
+	case opToken.keyword("in"):
+		fp.next()
+		right, numeric, err := fp.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return filterCompare{op: "in", left: left, right: right, numeric: numeric}, nil
+	case opToken.kind == filterTokenOperator:
+		fp.next()
+	default:
+		if left.field == nil {
+			return nil, fp.errorf(opToken, "expected an operator after %s, got %s", leftToken, opToken)
+		}
+		return filterExists{left}, nil
+	}
+
+	rightToken := fp.peek()
+	right, err := fp.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch opToken.value {
+	case "=~", "!~":
+		if rightToken.kind != filterTokenString {
+			return nil, fp.errorf(rightToken, "%s expects a regular expression string, got %s", opToken.value, rightToken)
+		}
+		re, err := regexp.Compile(rightToken.value)
+		if err != nil {
+			return nil, fp.errorf(rightToken, "invalid regular expression: %v", err)
+		}
+		return filterRegexp{operand: left, re: re, negate: opToken.value == "!~"}, nil
+	case "<", "<=", ">", ">=":
+		if leftToken.kind != filterTokenNumber && rightToken.kind != filterTokenNumber {
+			return nil, fp.errorf(opToken, "%s expects a number on one side", opToken.value)
+		}
+	}
+	numeric := leftToken.kind == filterTokenNumber || rightToken.kind == filterTokenNumber
+	if numeric && (leftToken.kind == filterTokenString || rightToken.kind == filterTokenString) {
+		return nil, fp.errorf(opToken, "cannot compare a number with a string")
+	}
+	// Numeric comparisons are written with the field first so values that are not numbers never match.
+	if leftToken.kind == filterTokenNumber {
+		left, right = right, left
+		opToken.value = flipFilterOperator(opToken.value)
+	}
+	return filterCompare{op: opToken.value, left: left, right: right, numeric: numeric}, nil
+}
+
+func flipFilterOperator(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+func (fp *filterParser) parseOperand() (filterOperand, error) {
+	t := fp.next()
+	switch t.kind {
+	case filterTokenString, filterTokenNumber:
+		return filterOperand{values: []string{t.value}}, nil
+	case filterTokenIdent:
+		for _, k := range []string{"and", "or", "not", "in"} {
+			if t.keyword(k) {
+				return filterOperand{}, fp.errorf(t, "expected a field or a value, got %s", t)
+			}
+		}
+		field := filterField(strings.ToLower(t.value))
+		if field == nil {
+			return filterOperand{}, fp.errorf(t, "unknown field %q", t.value)
+		}
+		return filterOperand{field: field}, nil
+	}
+	return filterOperand{}, fp.errorf(t, "expected a field or a value, got %s", t)
+}
+
+// parseList parses the right side of "in", either a field or a parenthesised list of values.
+// Lists holding only numbers are compared numerically.
+func (fp *filterParser) parseList() (filterOperand, bool, error) {
+	if fp.peek().kind != filterTokenLParen {
+		t := fp.peek()
+		operand, err := fp.parseOperand()
+		if err != nil {
+			return operand, false, err
+		}
+		if operand.field == nil {
+			return operand, false, fp.errorf(t, "in expects a field or a list like (\"a\", \"b\"), got %s", t)
+		}
+		return operand, false, nil
+	}
+	fp.next()
+	operand := filterOperand{values: []string{}}
+	numeric := true
+	for {
+		t := fp.next()
+		if t.kind != filterTokenString && t.kind != filterTokenNumber {
+			return operand, false, fp.errorf(t, "expected a value in the list, got %s", t)
+		}
+		operand.values = append(operand.values, t.value)
+		numeric = numeric && t.kind == filterTokenNumber
+		t = fp.next()
+		if t.kind == filterTokenRParen {
+			return operand, numeric, nil
+		}
+		if t.kind != filterTokenComma {
+			return operand, false, fp.errorf(t, "expected \",\" or \")\" in the list, got %s", t)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package anitogo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	subsPlease := Parse("[SubsPlease] One Piece - 1071 (1080p) [ABCD1234].mkv", DefaultOptions)
+	hardsub := Parse("[Erai-raws] Spy x Family Season 2 - 03 [1080p][HARDSUB][Multiple Subtitle].mkv", DefaultOptions)
+	horrible := Parse("[HorribleSubs] Boku no Hero Academia - 01 [720p].mkv", DefaultOptions)
+
+	testCases := []struct {
+		expr     string
+		expected []bool
+	}{
+		{`resolution >= 1080 and group in ("SubsPlease","Erai-raws") and not "HARDSUB" in subtitles`, []bool{true, false, false}},
+		{`resolution >= 1080`, []bool{true, true, false}},
+		{`1080 <= resolution`, []bool{true, true, false}},
+		{`episode_number > 1000 or anime_season == 2`, []bool{true, true, false}},
+		{`episode in (1, 3)`, []bool{false, true, true}},
+		{`title =~ "(?i)^one piece"`, []bool{true, false, false}},
+		{`anime_title !~ 'Piece'`, []bool{false, true, true}},
+		{`release_group == "subsplease"`, []bool{true, false, false}},
+		{`file_checksum`, []bool{true, false, false}},
+		{`not (file_checksum or release_group != "HorribleSubs")`, []bool{false, false, true}},
+		{`NOT file_checksum AND resolution < 1080`, []bool{false, false, true}},
+	}
+
+	for _, tc := range testCases {
+		f, err := CompileFilter(tc.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.expr, err)
+			continue
+		}
+		for i, e := range []*Elements{subsPlease, hardsub, horrible} {
+			if got := f.Match(e); got != tc.expected[i] {
+				t.Errorf("%s: expected %t for %s, got %t", tc.expr, tc.expected[i], e.FileName, got)
+			}
+		}
+	}
+}
+
+func TestFilterCompileFilter(t *testing.T) {
+	testCases := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{`resolution >= 1080 and`, 22, "expected a field or a value"},
+		{`groups == "A"`, 0, "unknown field \"groups\""},
+		{`title =~ "("`, 9, "invalid regular expression"},
+		{`title =~ 1`, 9, "expects a regular expression string"},
+		{`title > "A"`, 6, "expects a number on one side"},
+		{`episode == "1" and resolution = 1080`, 30, "did you mean \"==\"?"},
+		{`group in ("A" "B")`, 14, "expected \",\" or \")\""},
+		{`(resolution >= 1080`, 19, "expected \")\""},
+		{`"A" and title`, 4, "expected an operator"},
+		{`title == "A`, 9, "unterminated string"},
+		{`title == "A" title`, 13, "unexpected \"title\""},
+		{`título == "A"`, 0, "unknown field \"título\""},
+		{`title == "A" → "B"`, 13, "unexpected character '→'"},
+	}
+
+	for _, tc := range testCases {
+		_, err := CompileFilter(tc.expr)
+		var fe *FilterError
+		if !errors.As(err, &fe) {
+			t.Errorf("%s: expected a FilterError, got %v", tc.expr, err)
+			continue
+		}
+		if fe.Pos != tc.pos || !strings.Contains(fe.Msg, tc.msg) {
+			t.Errorf("%s: expected %q at %d, got %q at %d", tc.expr, tc.msg, tc.pos, fe.Msg, fe.Pos)
+		}
+	}
+}
@@ -5,4 +5,5 @@ go 1.21.5
 require (
 	github.com/google/uuid v1.5.0
 	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,111 @@
+package hashing
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ED2KChunkSize is the size of the chunks an ED2K hash is built from: 9500 KiB.
+const ED2KChunkSize = 9500 * 1024
+
+// ErrInvalidED2KLink is returned when an ed2k:// link cannot be parsed.
+var ErrInvalidED2KLink = errors.New("hashing: invalid ed2k link")
+
+// ed2kDigest computes the ED2K hash used by eDonkey and AniDB: the MD4 of the file if it fits in
+// a single chunk, otherwise the MD4 of the concatenated MD4 hashes of its chunks. A file whose size
+// is a multiple of the chunk size has no trailing empty chunk, as in eMule 0.50 and later.
+type ed2kDigest struct {
+	chunk   hash.Hash
+	inChunk int
+	hashes  []byte
+}
+
+// newED2K returns a new hash.Hash computing the ED2K hash.
+func newED2K() hash.Hash {
+	return &ed2kDigest{chunk: newMD4()}
+}
+
+func (d *ed2kDigest) Reset() {
+	d.chunk.Reset()
+	d.inChunk = 0
+	d.hashes = d.hashes[:0]
+}
+
+func (d *ed2kDigest) Size() int { return md4Size }
+
+func (d *ed2kDigest) BlockSize() int { return md4BlockSize }
+
+func (d *ed2kDigest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		c := min(len(p), ED2KChunkSize-d.inChunk)
+		d.chunk.Write(p[:c])
+		d.inChunk += c
+		p = p[c:]
+		if d.inChunk == ED2KChunkSize {
+			d.hashes = d.chunk.Sum(d.hashes)
+			d.chunk.Reset()
+			d.inChunk = 0
+		}
+	}
+	return n, nil
+}
+
+func (d *ed2kDigest) Sum(in []byte) []byte {
+	hashes := d.hashes
+	if d.inChunk > 0 || len(hashes) == 0 {
+		hashes = d.chunk.Sum(append([]byte{}, hashes...))
+	}
+	if len(hashes) == md4Size {
+		return append(in, hashes...)
+	}
+	h := newMD4()
+	h.Write(hashes)
+	return h.Sum(in)
+}
+
+// ED2KLink returns the ed2k:// link of a file, e.g "ed2k://|file|Title%20-%2001.mkv|1024|31d6cfe0d16ae931b73c59d7e0c089c0|/".
+func ED2KLink(name string, size int64, ed2k string) string {
+	return fmt.Sprintf("ed2k://|file|%s|%d|%s|/", url.PathEscape(name), size, strings.ToLower(ed2k))
+}
+
+// ParseED2KLink returns the name, size and ED2K hash of an ed2k:// file link.
+func ParseED2KLink(link string) (string, int64, string, error) {
+	rest, found := strings.CutPrefix(link, "ed2k://|file|")
+	if !found {
+		return "", 0, "", fmt.Errorf("%w %q: not a file link", ErrInvalidED2KLink, link)
+	}
+	fields := strings.Split(rest, "|")
+	if len(fields) < 3 {
+		return "", 0, "", fmt.Errorf("%w %q: expected a name, size and hash", ErrInvalidED2KLink, link)
+	}
+	name, err := url.PathUnescape(fields[0])
+	if err != nil {
+		name = fields[0]
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || size < 0 {
+		return "", 0, "", fmt.Errorf("%w %q: invalid size %q", ErrInvalidED2KLink, link, fields[1])
+	}
+	if !isHex(fields[2], md4Size) {
+		return "", 0, "", fmt.Errorf("%w %q: invalid hash %q", ErrInvalidED2KLink, link, fields[2])
+	}
+	return name, size, strings.ToLower(fields[2]), nil
+}
+
+// isHex returns true if s is the hex encoding of n bytes.
+func isHex(s string, n int) bool {
+	if len(s) != n*2 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
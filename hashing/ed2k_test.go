@@ -0,0 +1,70 @@
+package hashing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func md4Sum(p []byte) []byte {
+	h := newMD4()
+	h.Write(p)
+	return h.Sum(nil)
+}
+
+func TestED2K(t *testing.T) {
+	data := make([]byte, 2*ED2KChunkSize+100)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	chunks := func(sizes ...int) []byte {
+		var hashes []byte
+		offset := 0
+		for _, size := range sizes {
+			hashes = append(hashes, md4Sum(data[offset:offset+size])...)
+			offset += size
+		}
+		return hashes
+	}
+	tests := []struct {
+		size     int
+		expected []byte
+	}{
+		{0, md4Sum(nil)},
+		{100, md4Sum(data[:100])},
+		{ED2KChunkSize, md4Sum(data[:ED2KChunkSize])},
+		{ED2KChunkSize + 1, md4Sum(chunks(ED2KChunkSize, 1))},
+		{2 * ED2KChunkSize, md4Sum(chunks(ED2KChunkSize, ED2KChunkSize))},
+		{len(data), md4Sum(chunks(ED2KChunkSize, ED2KChunkSize, 100))},
+	}
+	for _, test := range tests {
+		h := newED2K()
+		// Write across chunk boundaries.
+		for offset := 0; offset < test.size; offset += 1 << 20 {
+			h.Write(data[offset:min(offset+1<<20, test.size)])
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, test.expected) {
+			t.Errorf("expected %x for %d bytes, got %x", test.expected, test.size, got)
+		}
+	}
+}
+
+func TestED2KLink(t *testing.T) {
+	ed2k := hex.EncodeToString(md4Sum(nil))
+	link := ED2KLink("[Group] Title - 01.mkv", 1024, ed2k)
+	expected := "ed2k://|file|%5BGroup%5D%20Title%20-%2001.mkv|1024|31d6cfe0d16ae931b73c59d7e0c089c0|/"
+	if link != expected {
+		t.Errorf("expected %s, got %s", expected, link)
+	}
+
+	name, size, hash, err := ParseED2KLink(link)
+	if err != nil || name != "[Group] Title - 01.mkv" || size != 1024 || hash != ed2k {
+		t.Errorf("expected the link to round-trip, got %q %d %q %v", name, size, hash, err)
+	}
+	for _, invalid := range []string{"magnet:?xt=urn:btih:00", "ed2k://|file|name|12|/", "ed2k://|file|name|-1|" + ed2k + "|/", "ed2k://|file|name|12|xyz|/"} {
+		if _, _, _, err := ParseED2KLink(invalid); !errors.Is(err, ErrInvalidED2KLink) {
+			t.Errorf("expected %v for %q, got %v", ErrInvalidED2KLink, invalid, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package hashing computes the ED2K, CRC32, MD5 and SHA-1 hashes of files in a single pass
+// and keeps an index of known hashes to identify files whatever their name.
+package hashing
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sums are the hashes of a file in lowercase hex, except CRC32 which is in uppercase hex
+// like the checksums embedded in file names.
+type Sums struct {
+	Size  int64  `json:"size"`
+	ED2K  string `json:"ed2k"`
+	CRC32 string `json:"crc32"`
+	MD5   string `json:"md5"`
+	SHA1  string `json:"sha1"`
+}
+
+// ED2KLink returns the ed2k:// link of a file with these sums.
+func (s Sums) ED2KLink(name string) string {
+	return ED2KLink(name, s.Size, s.ED2K)
+}
+
+// Hasher computes every hash of the data written to it.
+type Hasher struct {
+	size  int64
+	ed2k  hash.Hash
+	crc32 hash.Hash32
+	md5   hash.Hash
+	sha1  hash.Hash
+	w     io.Writer
+}
+
+// NewHasher returns a Hasher with nothing written to it.
+func NewHasher() *Hasher {
+	h := &Hasher{ed2k: newED2K(), crc32: crc32.NewIEEE(), md5: md5.New(), sha1: sha1.New()}
+	h.w = io.MultiWriter(h.ed2k, h.crc32, h.md5, h.sha1)
+	return h
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	n, err := h.w.Write(p)
+	h.size += int64(n)
+	return n, err
+}
+
+// Sums returns the hashes of the data written so far.
+func (h *Hasher) Sums() Sums {
+	return Sums{
+		Size:  h.size,
+		ED2K:  hex.EncodeToString(h.ed2k.Sum(nil)),
+		CRC32: fmt.Sprintf("%08X", h.crc32.Sum32()),
+		MD5:   hex.EncodeToString(h.md5.Sum(nil)),
+		SHA1:  hex.EncodeToString(h.sha1.Sum(nil)),
+	}
+}
+
+// Compute reads r to its end and returns its hashes.
+func Compute(r io.Reader) (Sums, error) {
+	h := NewHasher()
+	if _, err := io.Copy(h, r); err != nil {
+		return Sums{}, err
+	}
+	return h.Sums(), nil
+}
+
+// File returns the hashes of the file at path.
+func File(path string) (Sums, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Sums{}, err
+	}
+	defer f.Close()
+	return Compute(f)
+}
+
+// FileED2KLink returns the ed2k:// link of the file at path.
+func FileED2KLink(path string) (string, error) {
+	s, err := File(path)
+	if err != nil {
+		return "", err
+	}
+	return s.ED2KLink(filepath.Base(path)), nil
+}
@@ -0,0 +1,40 @@
+package hashing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashingCompute(t *testing.T) {
+	s, err := Compute(strings.NewReader("abc"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := Sums{
+		Size:  3,
+		ED2K:  "a448017aaf21d8525fc10ae87aa6729d",
+		CRC32: "352441C2",
+		MD5:   "900150983cd24fb0d6963f7d28e17f72",
+		SHA1:  "a9993e364706816aba3e25717850c26c9cd0d89d",
+	}
+	if s != expected {
+		t.Errorf("expected %+v, got %+v", expected, s)
+	}
+}
+
+func TestHashingFileED2KLink(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "Title - 01.mkv")
+	if err := os.WriteFile(p, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link, err := FileED2KLink(p)
+	expected := "ed2k://|file|Title%20-%2001.mkv|3|a448017aaf21d8525fc10ae87aa6729d|/"
+	if err != nil || link != expected {
+		t.Errorf("expected %s, got %s (%v)", expected, link, err)
+	}
+	if _, err := File(filepath.Join(t.TempDir(), "missing.mkv")); err == nil {
+		t.Errorf("expected error for a missing file, got nil")
+	}
+}
@@ -0,0 +1,204 @@
+package hashing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// Entry is an episode identified by the size and ED2K hash of its file.
+type Entry struct {
+	Size          int64    `json:"size"`
+	ED2K          string   `json:"ed2k"`
+	AnimeTitle    string   `json:"anime_title"`
+	AnimeSeason   []string `json:"anime_season,omitempty"`
+	EpisodeNumber []string `json:"episode_number,omitempty"`
+	EpisodeTitle  string   `json:"episode_title,omitempty"`
+	ReleaseGroup  string   `json:"release_group,omitempty"`
+}
+
+// elements returns the entry as parsed elements.
+func (e Entry) elements() *anitogo.Elements {
+	return &anitogo.Elements{
+		AnimeTitle:    e.AnimeTitle,
+		AnimeSeason:   e.AnimeSeason,
+		EpisodeNumber: e.EpisodeNumber,
+		EpisodeTitle:  e.EpisodeTitle,
+		ReleaseGroup:  e.ReleaseGroup,
+	}
+}
+
+type indexKey struct {
+	size int64
+	ed2k string
+}
+
+// Index maps the size and ED2K hash of files to the episodes they hold.
+type Index struct {
+	entries map[indexKey]Entry
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{entries: map[indexKey]Entry{}}
+}
+
+// LoadIndex reads an index saved with Save. A missing file is an empty index.
+func LoadIndex(path string) (*Index, error) {
+	idx := NewIndex()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("hashing: index %s line %d: %w", path, line, err)
+		}
+		if !isHex(e.ED2K, md4Size) {
+			return nil, fmt.Errorf("hashing: index %s line %d: invalid ed2k hash %q", path, line, e.ED2K)
+		}
+		idx.Put(e)
+	}
+	return idx, scanner.Err()
+}
+
+// Save writes the index to path as JSON Lines sorted by hash. The file is replaced atomically.
+func (idx *Index) Save(path string) error {
+	entries := make([]Entry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ED2K != entries[j].ED2K {
+			return entries[i].ED2K < entries[j].ED2K
+		}
+		return entries[i].Size < entries[j].Size
+	})
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	// CreateTemp makes the file private, so it takes the mode of the index it replaces.
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Put adds the entry to the index, replacing any entry with the same size and hash.
+func (idx *Index) Put(e Entry) {
+	e.ED2K = strings.ToLower(e.ED2K)
+	idx.entries[indexKey{e.Size, e.ED2K}] = e
+}
+
+// Add records that the file with the sums holds the episode described by the elements.
+func (idx *Index) Add(s Sums, e *anitogo.Elements) {
+	idx.Put(Entry{
+		Size:          s.Size,
+		ED2K:          s.ED2K,
+		AnimeTitle:    e.AnimeTitle,
+		AnimeSeason:   e.AnimeSeason,
+		EpisodeNumber: e.EpisodeNumber,
+		EpisodeTitle:  e.EpisodeTitle,
+		ReleaseGroup:  e.ReleaseGroup,
+	})
+}
+
+// Lookup returns the entry of the file with the size and ED2K hash.
+func (idx *Index) Lookup(size int64, ed2k string) (Entry, bool) {
+	e, found := idx.entries[indexKey{size, strings.ToLower(ed2k)}]
+	return e, found
+}
+
+// Len returns the number of entries in the index.
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// MatchStatus tells how the index relates to what was parsed from a file name.
+type MatchStatus string
+
+const (
+	// MatchUnknown is used when the file is not in the index.
+	MatchUnknown MatchStatus = "unknown"
+
+	// MatchConfirmed is used when the index holds the same title, season and episode as the file name.
+	MatchConfirmed MatchStatus = "confirmed"
+
+	// MatchOverridden is used when the index disagrees with the file name and its episode was used instead.
+	MatchOverridden MatchStatus = "overridden"
+)
+
+// Match is the outcome of identifying a file with the index.
+type Match struct {
+	Status MatchStatus `json:"status"`
+
+	// Elements parsed from the file name, with the title, season, episode, episode title and release
+	// group of the index entry if it was overridden.
+	Elements *anitogo.Elements `json:"elements"`
+
+	// Entry of the file in the index. Nil if the file is unknown.
+	Entry *Entry `json:"entry,omitempty"`
+}
+
+// Identify checks what was parsed from the name of a file against the index entry of its sums.
+// The elements are not modified; an overridden match holds a copy.
+func (idx *Index) Identify(e *anitogo.Elements, s Sums) Match {
+	entry, found := idx.Lookup(s.Size, s.ED2K)
+	if !found {
+		return Match{Status: MatchUnknown, Elements: e}
+	}
+	if e.SameEpisode(entry.elements()) {
+		return Match{Status: MatchConfirmed, Elements: e, Entry: &entry}
+	}
+
+	overridden := *e
+	overridden.AnimeTitle = entry.AnimeTitle
+	overridden.AnimeSeason = entry.AnimeSeason
+	overridden.EpisodeNumber = entry.EpisodeNumber
+	if entry.EpisodeTitle != "" {
+		overridden.EpisodeTitle = entry.EpisodeTitle
+	}
+	if entry.ReleaseGroup != "" {
+		overridden.ReleaseGroup = entry.ReleaseGroup
+	}
+	return Match{Status: MatchOverridden, Elements: &overridden, Entry: &entry}
+}
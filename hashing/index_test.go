@@ -0,0 +1,84 @@
+package hashing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestIndexIdentify(t *testing.T) {
+	idx := NewIndex()
+	known := Sums{Size: 3, ED2K: "A448017AAF21D8525FC10AE87AA6729D"}
+	idx.Add(known, &anitogo.Elements{AnimeTitle: "Title", AnimeSeason: []string{"2"}, EpisodeNumber: []string{"5"}, ReleaseGroup: "Group"})
+
+	confirmed := anitogo.Parse("[Group] Title S2 - 05.mkv", anitogo.DefaultOptions)
+	if m := idx.Identify(confirmed, known); m.Status != MatchConfirmed || m.Elements != confirmed {
+		t.Errorf("expected %s with the parsed elements, got %s", MatchConfirmed, m.Status)
+	}
+
+	guessed := anitogo.Parse("[Group] Title - 17.mkv", anitogo.DefaultOptions)
+	m := idx.Identify(guessed, known)
+	if m.Status != MatchOverridden {
+		t.Fatalf("expected %s, got %s", MatchOverridden, m.Status)
+	}
+	if !reflect.DeepEqual(m.Elements.EpisodeNumber, []string{"5"}) || !reflect.DeepEqual(m.Elements.AnimeSeason, []string{"2"}) {
+		t.Errorf("expected S2 episode 5 from the index, got %v %v", m.Elements.AnimeSeason, m.Elements.EpisodeNumber)
+	}
+	if guessed.EpisodeNumber[0] != "17" {
+		t.Errorf("expected the parsed elements to be left unchanged, got %v", guessed.EpisodeNumber)
+	}
+
+	if m := idx.Identify(guessed, Sums{Size: 4, ED2K: known.ED2K}); m.Status != MatchUnknown {
+		t.Errorf("expected %s for another size, got %s", MatchUnknown, m.Status)
+	}
+}
+
+func TestIndexSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.jsonl")
+	idx, err := LoadIndex(path)
+	if err != nil || idx.Len() != 0 {
+		t.Fatalf("expected an empty index for a missing file, got %d entries (%v)", idx.Len(), err)
+	}
+	idx.Put(Entry{Size: 3, ED2K: "a448017aaf21d8525fc10ae87aa6729d", AnimeTitle: "Title", EpisodeNumber: []string{"1"}})
+	idx.Put(Entry{Size: 0, ED2K: "31d6cfe0d16ae931b73c59d7e0c089c0", AnimeTitle: "Other"})
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	loaded, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(loaded.entries, idx.entries) {
+		t.Errorf("expected %v, got %v", idx.entries, loaded.entries)
+	}
+	b, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(b), `{"size":0,"ed2k":"31d6`) {
+		t.Errorf("expected entries sorted by hash, got %s", b)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o644 {
+		t.Errorf("expected a new index with mode 0644, got %v", info.Mode().Perm())
+	}
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0o640 {
+		t.Errorf("expected the index to keep mode 0640, got %v", info.Mode().Perm())
+	}
+
+	os.WriteFile(path, []byte(`{"size":1,"ed2k":"nope"}`+"\n"), 0o644)
+	if _, err := LoadIndex(path); err == nil {
+		t.Errorf("expected error for an invalid hash, got nil")
+	}
+}
@@ -0,0 +1,118 @@
+package hashing
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// md4Size and md4BlockSize are the sizes of an MD4 digest and block in bytes.
+const (
+	md4Size      = 16
+	md4BlockSize = 64
+)
+
+// md4Digest implements the MD4 hash function of RFC 1320, which ED2K hashes are built from.
+// MD4 is broken and must not be used for anything but compatibility.
+type md4Digest struct {
+	s   [4]uint32
+	x   [md4BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// newMD4 returns a new hash.Hash computing the MD4 checksum.
+func newMD4() hash.Hash {
+	d := &md4Digest{}
+	d.Reset()
+	return d
+}
+
+func (d *md4Digest) Reset() {
+	d.s = [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *md4Digest) Size() int { return md4Size }
+
+func (d *md4Digest) BlockSize() int { return md4BlockSize }
+
+func (d *md4Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.x[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+		if d.nx == md4BlockSize {
+			d.block(d.x[:])
+			d.nx = 0
+		}
+	}
+	for len(p) >= md4BlockSize {
+		d.block(p[:md4BlockSize])
+		p = p[md4BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return n, nil
+}
+
+func (d *md4Digest) Sum(in []byte) []byte {
+	// Pad a copy so the caller can keep writing.
+	c := *d
+	length := c.len << 3
+	var pad [md4BlockSize + 8]byte
+	pad[0] = 0x80
+	if c.len%md4BlockSize < 56 {
+		c.Write(pad[:56-c.len%md4BlockSize])
+	} else {
+		c.Write(pad[:md4BlockSize+56-c.len%md4BlockSize])
+	}
+	binary.LittleEndian.PutUint64(pad[:8], length)
+	c.Write(pad[:8])
+
+	var out [md4Size]byte
+	for i, s := range c.s {
+		binary.LittleEndian.PutUint32(out[i*4:], s)
+	}
+	return append(in, out[:]...)
+}
+
+var (
+	md4Shifts1 = [4]int{3, 7, 11, 19}
+	md4Shifts2 = [4]int{3, 5, 9, 13}
+	md4Shifts3 = [4]int{3, 9, 11, 15}
+
+	md4Order2 = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	md4Order3 = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+// block processes a 64 byte block.
+func (d *md4Digest) block(p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[i*4:])
+	}
+	a, b, c, e := d.s[0], d.s[1], d.s[2], d.s[3]
+
+	for i := 0; i < 16; i++ {
+		f := (b & c) | (^b & e)
+		a, b, c, e = e, bits.RotateLeft32(a+f+x[i], md4Shifts1[i%4]), b, c
+	}
+	for i := 0; i < 16; i++ {
+		g := (b & c) | (b & e) | (c & e)
+		a, b, c, e = e, bits.RotateLeft32(a+g+x[md4Order2[i]]+0x5a827999, md4Shifts2[i%4]), b, c
+	}
+	for i := 0; i < 16; i++ {
+		h := b ^ c ^ e
+		a, b, c, e = e, bits.RotateLeft32(a+h+x[md4Order3[i]]+0x6ed9eba1, md4Shifts3[i%4]), b, c
+	}
+
+	d.s[0] += a
+	d.s[1] += b
+	d.s[2] += c
+	d.s[3] += e
+}
@@ -0,0 +1,30 @@
+package hashing
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMD4(t *testing.T) {
+	// Test suite of RFC 1320.
+	tests := map[string]string{
+		"":                           "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"a":                          "bde52cb31de33e46245e05fbdbd6fb24",
+		"abc":                        "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest":             "d9130a8164549fe818874806e1c7014b",
+		"abcdefghijklmnopqrstuvwxyz": "d79e1c308aa5bbcdeea8ed63df412da9",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789": "043f8582f241db351ce627e153e7f0e4",
+		strings.Repeat("1234567890", 8):                                  "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, expected := range tests {
+		h := newMD4()
+		// Write in uneven pieces to exercise the buffering.
+		for i := 0; i < len(input); i += 7 {
+			h.Write([]byte(input[i:min(i+7, len(input))]))
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != expected {
+			t.Errorf("expected %s for %q, got %s", expected, input, got)
+		}
+	}
+}
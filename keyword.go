@@ -21,8 +21,9 @@ type keywordOption struct {
 }
 
 type keyword struct {
-	category elementCategory
-	options  keywordOption
+	category  elementCategory
+	options   keywordOption
+	canonical string
 }
 
 type keywordManager struct {
@@ -80,7 +81,8 @@ func newKeywordManager() *keywordManager {
 		"FLACX2", "FLACX3", "FLACX4", "LOSSLESS", "MP3", "OGG", "VORBIS",
 		"DD2", "DD2.0", "ATMOS", "DOLBY ATMOS",
 		// Audio language
-		"DUALAUDIO", "DUAL AUDIO"})
+		"DUALAUDIO", "DUAL AUDIO", "DUAL-AUDIO",
+		"MULTIAUDIO", "MULTI AUDIO", "MULTI-AUDIO"})
 	kwm.add(elementCategoryAudioTerm, keywordOptionsUnidentifiable, []string{
 		"OPUS", // e.g "Opus.COLORs"
 	})
@@ -88,6 +90,11 @@ func newKeywordManager() *keywordManager {
 		"IPAD3", "IPHONE5", "IPOD", "PS3", "XBOX", "XBOX360"})
 	kwm.add(elementCategoryDeviceCompatibility, keywordOptionsUnidentifiable, []string{
 		"ANDROID"})
+	kwm.add(elementCategoryEdition, keywordOptionsUnidentifiableUnsearchable, []string{
+		"BD EDIT", "BD VERSION", "DIRECTOR'S CUT", "DIRECTORS CUT",
+		"EXTENDED", "EXTENDED CUT", "EXTENDED EDITION", "EXTENDED VERSION",
+		"RECAP", "SPECIAL EDITION", "THEATRICAL", "THEATRICAL CUT",
+		"THEATRICAL EDITION", "THEATRICAL VERSION", "TV CUT", "TV EDIT", "TV VERSION"}) // Matched as phrases
 	kwm.add(elementCategoryEpisodePrefix, keywordOptionsDefault, []string{
 		"EP", "EP.", "EPS", "EPS.", "EPISODE", "EPISODE.", "EPISODES",
 		"CAPITULO", "EPISODIO", "EPISóDIO", "FOLGE"})
@@ -104,47 +111,83 @@ func newKeywordManager() *keywordManager {
 	kwm.add(elementCategoryLanguage, keywordOptionsUnidentifiable, []string{
 		"ESP", "ITA"}) // e.g "Tokyo ESP", "Bokura ga Ita"
 	kwm.add(elementCategoryOther, keywordOptionsDefault, []string{
-		"REMASTER", "REMASTERED", "UNCENSORED", "UNCUT", "TS", "VFR",
+		"CENSORED", "DECENSORED", "REMASTER", "REMASTERED", "UNCENSORED", "UNCUT", "TS", "VFR",
 		"WIDESCREEN", "WS"})
 	kwm.add(elementCategoryReleaseGroup, keywordOptionsDefault, []string{
 		"THORA", "HORRIBLESUBS", "ERAI-RAWS"})
 	kwm.add(elementCategoryReleaseInformation, keywordOptionsDefault, []string{
-		"BATCH", "COMPLETE", "PATCH", "REMUX"})
+		"BATCH", "COMPLETE", "PATCH", "REMUX",
+		"DIRFIX", "NFOFIX", "NUKED", "READNFO", "REPACK", "RERIP"})
+	kwm.add(elementCategoryReleaseInformation, keywordOptionsUnidentifiableUnsearchable, []string{
+		"INTERNAL", "PROPER"}) // e.g "Internal Affairs", only accepted when not in title case
 	kwm.add(elementCategoryReleaseInformation, keywordOptionsUnidentifiable, []string{
 		"END", "FINAL"}) // e.g "The End of Evangelion", "Final Approach"
 	kwm.add(elementCategoryReleaseVersion, keywordOptionsDefault, []string{
-		"V0", "V1", "V2", "V3", "V4"})
+		"V0", "V1", "V2", "V3", "V4", "V5", "V6", "V7", "V8", "V9"})
 	kwm.add(elementCategorySource, keywordOptionsDefault, []string{
 		"BD", "BDRIP", "BLURAY", "BLU-RAY", "DVD", "DVD5", "DVD9",
 		"DVD-R2J", "DVDRIP", "DVD-RIP", "R2DVD", "R2J", "R2JDVD",
 		"R2JDVDRIP", "HDTV", "HDTVRIP", "TVRIP", "TV-RIP",
-		"WEBCAST", "WEBRIP"})
+		"WEB-DL", "WEBDL", "WEBCAST", "WEBRIP", "WEB-RIP",
+		"LASERDISC", "LDRIP", "VHSRIP"})
+	kwm.add(elementCategorySource, keywordOptionsUnidentifiableUnsearchable, []string{
+		"WEB", "VHS"}) // e.g "Web Ghosts", only accepted next to other identifiers
+	kwm.add(elementCategoryStreamingService, keywordOptionsDefault, []string{
+		"ABEMA", "ANIMELAB", "B-GLOBAL", "BILIBILI", "CRUNCHYROLL", "FUNIMATION",
+		"HIDIVE", "IQIYI", "NETFLIX", "U-NEXT", "WAKANIM"})
+	kwm.add(elementCategoryStreamingService, keywordOptionsUnidentifiableUnsearchable, []string{
+		"ADN", "AMZN", "ATVP", "BAHA", "BGLOBAL", "CR", "DSNP", "FUNI", "HMAX",
+		"HULU", "NF", "VRV", "WKN"}) // Short codes are ambiguous, e.g "NF" in a title
 	kwm.add(elementCategorySubtitles, keywordOptionsDefault, []string{
 		"ASS", "BIG5", "DUB", "DUBBED", "HARDSUB", "HARDSUBS", "RAW",
 		"SOFTSUB", "SOFTSUBS", "SUB", "SUBBED", "SUBTITLED",
-	        "MULTISUB", "MULTI SUB"})
+		"MULTISUB", "MULTISUBS", "MULTI-SUB", "MULTI-SUBS", "MULTI SUB"})
 	kwm.add(elementCategoryVideoTerm, keywordOptionsDefault, []string{
 		// Frame rate
-		"23.976FPS", "24FPS", "29.97FPS", "30FPS", "60FPS", "120FPS",
+		"23.976FPS", "23.98FPS", "24FPS", "25FPS", "29.97FPS", "30FPS",
+		"48FPS", "50FPS", "59.94FPS", "60FPS", "120FPS",
 		// Video codec
 		"8BIT", "8-BIT", "10BIT", "10BITS", "10-BIT", "10-BITS",
+		"12BIT", "12BITS", "12-BIT", "12-BITS",
 		"HI10", "HI10P", "HI444", "HI444P", "HI444PP",
-		"HDR", "DV", "DOLBY VISION",
-		"H264", "H265", "H.264", "H.265", "X264", "X265", "X.264",
+		"HDR", "HDR10", "HDR10+", "HDR10PLUS", "HLG",
+		"DV", "DOVI", "DOLBY VISION",
+		"H264", "H265", "H.264", "H.265", "X264", "X265", "X.264", "X.265",
 		"AVC", "HEVC", "HEVC2", "DIVX", "DIVX5", "DIVX6", "XVID",
-		"AV1",
+		"AV1", "SVT-AV1", "VC-1", "VC1", "VP9", "MPEG2", "MPEG-2",
+		// Video encoder
+		"NVENC",
 		// Video format
 		"AVI", "RMVB", "WMV", "WMV3", "WMV9",
 		// Video quality
 		"HQ", "LQ",
 		// Video resolution
-		"4K", "HD", "SD"})
+		"4K", "8K", "HD", "SD", "FHD", "QHD", "UHD"})
 	kwm.add(elementCategoryVolumePrefix, keywordOptionsDefault, []string{
 		"VOL", "VOL.", "VOLUME"})
 
+	addCanonicalValues(kwm)
+
 	return kwm
 }
 
+// Keywords returns the keywords recognised by the parser, sorted and grouped by the JSON name
+// of their element, e.g "video_term". Keywords that can belong to several elements are under "unknown".
+func Keywords() map[string][]string {
+	kwm := getSharedKeywordManager()
+	keywords := map[string][]string{}
+	for _, table := range []map[string]keyword{kwm.keywords, kwm.fileExtensions} {
+		for kw, kd := range table {
+			name := elementCategoryNames[kd.category]
+			keywords[name] = append(keywords[name], kw)
+		}
+	}
+	for _, kws := range keywords {
+		sort.Strings(kws)
+	}
+	return keywords
+}
+
 func (kd keyword) empty() bool {
 	return kd == keyword{}
 }
@@ -165,6 +208,16 @@ func (kwm *keywordManager) add(cat elementCategory, opt keywordOption, keywords
 	}
 }
 
+func (kwm *keywordManager) setCanonical(cat elementCategory, canonical string, keywords []string) {
+	for _, kw := range keywords {
+		v, ok := kwm.keywords[kw]
+		if ok && v.category == cat {
+			v.canonical = canonical
+			kwm.keywords[kw] = v
+		}
+	}
+}
+
 func (kwm *keywordManager) find(word string, cat elementCategory) (keyword, bool) {
 	if cat != elementCategoryFileExtension {
 		v, ok := kwm.keywords[word]
@@ -194,9 +247,9 @@ func (kwm *keywordManager) findWithoutCategory(word string) (keyword, bool) {
 
 func (kwm *keywordManager) peek(word string, e *Elements) indexSets {
 	entries := map[elementCategory][]string{
-		elementCategoryAudioTerm:       {"Dual Audio", "DualAudio"},
-		elementCategoryVideoTerm:       {"H264", "H.264", "h264", "h.264"},
-		elementCategoryVideoResolution: {"480p", "720p", "1080p", "1440p", "2160p"},
+		elementCategoryAudioTerm:       {"Dual Audio", "DualAudio", "Multi Audio", "Dolby TrueHD", "Dolby Atmos"},
+		elementCategoryVideoTerm:       {"H264", "H.264", "h264", "h.264", "H265", "H.265", "h265", "h.265", "Dolby Vision", "DOLBY VISION"},
+		elementCategoryVideoResolution: {"480p", "720p", "1080p", "1440p", "2160p", "4320p"},
 		elementCategorySource:          {"Blu-Ray"},
 	}
 
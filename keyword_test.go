@@ -1,6 +1,7 @@
 package anitogo
 
 import (
+	"sort"
 	"testing"
 )
 
@@ -78,3 +79,18 @@ func TestKeywordPeek(t *testing.T) {
 		t.Errorf("expected \"%s\", got \"%s\"", "Dual Audio", testStr[idxSets[0].beginPos:idxSets[0].endPos])
 	}
 }
+
+func TestKeywordKeywords(t *testing.T) {
+	keywords := Keywords()
+	expected := map[string]string{"file_extension": "MKV", "anime_type": "OVA", "anime_season_prefix": "SEASON", "volume_prefix": "VOLUME"}
+	for name, kw := range expected {
+		if !checkInList(keywords[name], kw) {
+			t.Errorf("expected %q in %q, got %v", kw, name, keywords[name])
+		}
+	}
+	for name, kws := range keywords {
+		if !sort.StringsAreSorted(kws) {
+			t.Errorf("expected the keywords of %q to be sorted", name)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package anitogo
+
+import "strings"
+
+const languageListSeparators = "+&/,"
+
+// LanguageTrack describes what a language found in the filename applies to.
+type LanguageTrack string
+
+const (
+	// LanguageTrackUnknown is used when the filename does not say whether the language is spoken or subtitled.
+	LanguageTrackUnknown LanguageTrack = ""
+
+	// LanguageTrackAudio is used for spoken languages, e.g "ENG DUB".
+	LanguageTrackAudio LanguageTrack = "audio"
+
+	// LanguageTrackSubtitles is used for subtitle languages, e.g "ENGSUB" or "VOSTFR".
+	LanguageTrackSubtitles LanguageTrack = "subtitles"
+)
+
+// Language is a language found in the filename, normalised to its ISO 639 codes.
+type Language struct {
+	// ISO 639-1 code of the language, e.g "en". Empty for languages without one, such as "mul".
+	ISO6391 string `json:"iso639_1,omitempty"`
+
+	// ISO 639-3 code of the language, e.g "eng".
+	ISO6393 string `json:"iso639_3"`
+
+	// ISO 3166-1 region of the language, e.g "BR" for "PT-BR".
+	Region string `json:"region,omitempty"`
+
+	// ISO 15924 script of the language, e.g "Hant" for "BIG5".
+	Script string `json:"script,omitempty"`
+
+	// English name of the language, e.g "Portuguese".
+	Name string `json:"name"`
+
+	// Whether the language applies to the audio or the subtitles.
+	Track LanguageTrack `json:"track,omitempty"`
+
+	// The string the language was parsed from, as it appears in the filename.
+	Raw string `json:"raw"`
+}
+
+type languageEntry struct {
+	language Language
+
+	// Ambiguous entries are only accepted next to other languages or a subtitle/dub marker.
+	ambiguous bool
+}
+
+var languageTable = map[string]languageEntry{}
+
+var (
+	languageSubtitleMarkers = []string{
+		"SUB", "SUBS", "SUBBED", "SUBTITLE", "SUBTITLES", "SUBTITLED",
+		"SOFTSUB", "SOFTSUBS", "HARDSUB", "HARDSUBS"}
+	languageAudioMarkers = []string{
+		"DUB", "DUBS", "DUBBED", "AUDIO"}
+)
+
+func init() {
+	addLanguage(Language{ISO6391: "en", ISO6393: "eng", Name: "English"}, []string{"ENG", "ENGLISH"}, []string{"EN"})
+	addLanguage(Language{ISO6391: "ja", ISO6393: "jpn", Name: "Japanese"}, []string{"JAP", "JPN", "JAPANESE"}, []string{"JA", "JP"})
+	addLanguage(Language{ISO6391: "fr", ISO6393: "fra", Name: "French"}, []string{"FRENCH"}, []string{"FR", "FRA", "FRE"})
+	addLanguage(Language{ISO6391: "fr", ISO6393: "fra", Name: "French", Track: LanguageTrackAudio}, []string{"VFF"}, []string{"VF"})
+	addLanguage(Language{ISO6391: "fr", ISO6393: "fra", Region: "CA", Name: "French", Track: LanguageTrackAudio}, []string{"VFQ"}, nil)
+	addLanguage(Language{ISO6391: "fr", ISO6393: "fra", Name: "French", Track: LanguageTrackSubtitles}, []string{"VOSTFR"}, nil)
+	addLanguage(Language{ISO6391: "de", ISO6393: "deu", Name: "German"}, []string{"GERMAN"}, []string{"DE", "DEU", "GER"})
+	addLanguage(Language{ISO6391: "es", ISO6393: "spa", Name: "Spanish"}, []string{"ESPANOL", "SPANISH"}, []string{"ES", "ESP", "SPA"})
+	addLanguage(Language{ISO6391: "es", ISO6393: "spa", Region: "419", Name: "Spanish"}, []string{"ES-LA", "ES-419", "LATINO"}, nil)
+	addLanguage(Language{ISO6391: "es", ISO6393: "spa", Region: "ES", Name: "Spanish"}, []string{"ES-ES", "CASTELLANO"}, nil)
+	addLanguage(Language{ISO6391: "it", ISO6393: "ita", Name: "Italian"}, []string{"ITALIAN"}, []string{"IT", "ITA"})
+	addLanguage(Language{ISO6391: "pt", ISO6393: "por", Name: "Portuguese"}, []string{"PORTUGUESE"}, []string{"PT", "POR"})
+	addLanguage(Language{ISO6391: "pt", ISO6393: "por", Region: "BR", Name: "Portuguese"}, []string{"PT-BR", "PTBR"}, nil)
+	addLanguage(Language{ISO6391: "pt", ISO6393: "por", Region: "PT", Name: "Portuguese"}, []string{"PT-PT"}, nil)
+	addLanguage(Language{ISO6391: "ru", ISO6393: "rus", Name: "Russian"}, []string{"RUSSIAN"}, []string{"RU", "RUS"})
+	addLanguage(Language{ISO6391: "zh", ISO6393: "zho", Name: "Chinese"}, []string{"CHINESE"}, []string{"ZH", "CHI", "CHN"})
+	addLanguage(Language{ISO6391: "zh", ISO6393: "zho", Script: "Hans", Name: "Chinese"}, []string{"CHS", "ZH-HANS"}, nil)
+	addLanguage(Language{ISO6391: "zh", ISO6393: "zho", Script: "Hant", Name: "Chinese"}, []string{"CHT", "ZH-HANT"}, nil)
+	addLanguage(Language{ISO6391: "zh", ISO6393: "zho", Script: "Hant", Name: "Chinese", Track: LanguageTrackSubtitles}, []string{"BIG5"}, nil)
+	addLanguage(Language{ISO6391: "ko", ISO6393: "kor", Name: "Korean"}, []string{"KOREAN"}, []string{"KO", "KOR"})
+	addLanguage(Language{ISO6391: "ar", ISO6393: "ara", Name: "Arabic"}, []string{"ARABIC"}, []string{"AR", "ARA"})
+	addLanguage(Language{ISO6391: "pl", ISO6393: "pol", Name: "Polish"}, []string{"POLISH"}, []string{"PL", "POL"})
+	addLanguage(Language{ISO6391: "tr", ISO6393: "tur", Name: "Turkish"}, []string{"TURKISH"}, []string{"TR", "TUR"})
+	addLanguage(Language{ISO6391: "nl", ISO6393: "nld", Name: "Dutch"}, []string{"DUTCH"}, []string{"NL", "NLD", "DUT"})
+	addLanguage(Language{ISO6391: "vi", ISO6393: "vie", Name: "Vietnamese"}, []string{"VIETNAMESE"}, []string{"VI", "VIE"})
+	addLanguage(Language{ISO6391: "th", ISO6393: "tha", Name: "Thai"}, []string{"THAI"}, []string{"TH", "THA"})
+	addLanguage(Language{ISO6391: "id", ISO6393: "ind", Name: "Indonesian"}, []string{"INDONESIAN"}, []string{"ID", "IND"})
+	addLanguage(Language{ISO6391: "ms", ISO6393: "msa", Name: "Malay"}, []string{"MALAY"}, []string{"MS", "MSA", "MAY"})
+	addLanguage(Language{ISO6391: "hi", ISO6393: "hin", Name: "Hindi"}, []string{"HINDI"}, []string{"HI", "HIN"})
+	addLanguage(Language{ISO6391: "uk", ISO6393: "ukr", Name: "Ukrainian"}, []string{"UKRAINIAN"}, []string{"UK", "UKR"})
+	addLanguage(Language{ISO6393: "mul", Name: "Multiple languages"}, nil, []string{"MULTI"})
+}
+
+func addLanguage(lang Language, keywords, ambiguousKeywords []string) {
+	for _, kw := range keywords {
+		languageTable[kw] = languageEntry{language: lang}
+	}
+	for _, kw := range ambiguousKeywords {
+		languageTable[kw] = languageEntry{language: lang, ambiguous: true}
+	}
+}
+
+// Tag returns the BCP 47 style tag of the language, e.g "pt-BR" or "zh-Hant".
+func (l Language) Tag() string {
+	tag := l.ISO6391
+	if tag == "" {
+		tag = l.ISO6393
+	}
+	if l.Script != "" {
+		tag += "-" + l.Script
+	}
+	if l.Region != "" {
+		tag += "-" + l.Region
+	}
+	return tag
+}
+
+// AudioLanguages returns the languages that were identified as spoken languages.
+func (e *Elements) AudioLanguages() []Language {
+	return e.languagesWithTrack(LanguageTrackAudio)
+}
+
+// SubtitleLanguages returns the languages that were identified as subtitle languages.
+func (e *Elements) SubtitleLanguages() []Language {
+	return e.languagesWithTrack(LanguageTrackSubtitles)
+}
+
+func (e *Elements) languagesWithTrack(track LanguageTrack) []Language {
+	var langs []Language
+	for _, l := range e.Languages {
+		if l.Track == track {
+			langs = append(langs, l)
+		}
+	}
+	return langs
+}
+
+func (e *Elements) insertLanguage(lang Language) {
+	for i, l := range e.Languages {
+		if l.Tag() != lang.Tag() {
+			continue
+		}
+		if l.Track == lang.Track || lang.Track == LanguageTrackUnknown {
+			return
+		}
+		if l.Track == LanguageTrackUnknown {
+			e.Languages[i].Track = lang.Track
+			return
+		}
+	}
+	e.Languages = append(e.Languages, lang)
+}
+
+type languageMatch struct {
+	tkn       *token
+	languages []Language
+	ambiguous bool
+	track     LanguageTrack
+}
+
+func (p *parser) searchForLanguages() {
+	tkns := *p.tokenizer.tokens
+	for i := 0; i < len(tkns); i++ {
+		first, found := matchLanguageToken(tkns[i])
+		if !found {
+			continue
+		}
+
+		group := []languageMatch{first}
+		last := i
+		for last+2 < len(tkns) {
+			separator := tkns[last+1]
+			if separator.Category != tokenCategoryDelimiter || !strings.Contains(languageListSeparators, separator.Content) {
+				break
+			}
+			next, found := matchLanguageToken(tkns[last+2])
+			if !found {
+				break
+			}
+			group = append(group, next)
+			last += 2
+		}
+
+		track := p.findLanguageMarker(tkns, i, last)
+		count := 0
+		ambiguous := false
+		for _, m := range group {
+			count += len(m.languages)
+			ambiguous = ambiguous || m.ambiguous
+			if m.track != LanguageTrackUnknown {
+				track = m.track
+			}
+		}
+		if count < 2 && track == LanguageTrackUnknown {
+			if ambiguous || (first.tkn.Category != tokenCategoryIdentifier && !first.tkn.Enclosed) {
+				i = last
+				continue
+			}
+		}
+
+		for _, m := range group {
+			for _, lang := range m.languages {
+				if lang.Track == LanguageTrackUnknown {
+					lang.Track = track
+				}
+				p.tokenizer.elements.insertLanguage(lang)
+			}
+			if m.tkn.Category == tokenCategoryUnknown {
+				m.tkn.Category = tokenCategoryIdentifier
+			}
+		}
+		i = last
+	}
+}
+
+// findLanguageMarker looks for a subtitle or dub marker right before or after a group of languages, e.g "ENG SUB" or "Sub.FR".
+// Markers joined to the group by a list separator are list items of their own and do not apply to it.
+func (p *parser) findLanguageMarker(tkns tokens, begin, end int) LanguageTrack {
+	for i := end + 1; i < len(tkns); i++ {
+		if tkns[i].Category == tokenCategoryDelimiter {
+			if strings.Contains(languageListSeparators, tkns[i].Content) {
+				break
+			}
+			continue
+		}
+		if track := languageMarkerTrack(tkns[i].Content); track != LanguageTrackUnknown {
+			return track
+		}
+		break
+	}
+	for i := begin - 1; i >= 0; i-- {
+		if tkns[i].Category == tokenCategoryDelimiter {
+			if strings.Contains(languageListSeparators, tkns[i].Content) {
+				break
+			}
+			continue
+		}
+		return languageMarkerTrack(tkns[i].Content)
+	}
+	return LanguageTrackUnknown
+}
+
+func matchLanguageToken(tkn *token) (languageMatch, bool) {
+	if tkn.Category != tokenCategoryUnknown && tkn.Category != tokenCategoryIdentifier {
+		return languageMatch{}, false
+	}
+	content := strings.Trim(tkn.Content, " -")
+	if content == "" {
+		return languageMatch{}, false
+	}
+
+	m := languageMatch{tkn: tkn}
+	normalized := strings.ToUpper(content)
+	for _, suffixes := range [][]string{languageSubtitleMarkers, languageAudioMarkers} {
+		for _, suffix := range suffixes {
+			if len(normalized) > len(suffix) && strings.HasSuffix(normalized, suffix) {
+				m.track = languageMarkerTrack(suffix)
+				content = strings.TrimRight(content[:len(content)-len(suffix)], "-.")
+				break
+			}
+		}
+		if m.track != LanguageTrackUnknown {
+			break
+		}
+	}
+
+	parts := strings.FieldsFunc(content, func(r rune) bool {
+		return strings.ContainsRune(languageListSeparators, r)
+	})
+	if len(parts) == 0 {
+		return languageMatch{}, false
+	}
+	for _, part := range parts {
+		entry, found := languageTable[strings.ToUpper(part)]
+		if !found {
+			return languageMatch{}, false
+		}
+		lang := entry.language
+		lang.Raw = part
+		m.languages = append(m.languages, lang)
+		m.ambiguous = m.ambiguous || entry.ambiguous
+	}
+	return m, true
+}
+
+func languageMarkerTrack(content string) LanguageTrack {
+	normalized := strings.ToUpper(strings.Trim(content, " -."))
+	if checkInList(languageSubtitleMarkers, normalized) {
+		return LanguageTrackSubtitles
+	}
+	if checkInList(languageAudioMarkers, normalized) {
+		return LanguageTrackAudio
+	}
+	return LanguageTrackUnknown
+}
@@ -0,0 +1,85 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestLanguageTag(t *testing.T) {
+	l := Language{ISO6391: "pt", ISO6393: "por", Region: "BR"}
+	if l.Tag() != "pt-BR" {
+		t.Errorf("expected \"pt-BR\", got \"%s\"", l.Tag())
+	}
+	l = Language{ISO6391: "zh", ISO6393: "zho", Script: "Hant"}
+	if l.Tag() != "zh-Hant" {
+		t.Errorf("expected \"zh-Hant\", got \"%s\"", l.Tag())
+	}
+	l = Language{ISO6393: "mul"}
+	if l.Tag() != "mul" {
+		t.Errorf("expected \"mul\", got \"%s\"", l.Tag())
+	}
+}
+
+func TestLanguageSearchForLanguages(t *testing.T) {
+	e := Parse("[Group] Title - 05 [1080p][ENG+JPN+CHI].mkv", DefaultOptions)
+	if len(e.Languages) != 3 {
+		t.Fatalf("expected 3 languages, got %d", len(e.Languages))
+	}
+	for i, tag := range []string{"en", "ja", "zh"} {
+		if e.Languages[i].Tag() != tag {
+			t.Errorf("expected \"%s\", got \"%s\"", tag, e.Languages[i].Tag())
+		}
+	}
+	if e.AnimeTitle != "Title" {
+		t.Errorf("expected \"Title\", got \"%s\"", e.AnimeTitle)
+	}
+
+	e = Parse("[Group] Title - 05 [Multi-Subs][ENG/JPN DUB].mkv", DefaultOptions)
+	subs := e.SubtitleLanguages()
+	if len(subs) != 1 || subs[0].ISO6393 != "mul" {
+		t.Errorf("expected mul subtitles, got %v", subs)
+	}
+	audio := e.AudioLanguages()
+	if len(audio) != 2 || audio[0].ISO6393 != "eng" || audio[1].ISO6393 != "jpn" {
+		t.Errorf("expected eng and jpn audio, got %v", audio)
+	}
+
+	e = Parse("[Juuni.Kokki]-(Les.12.Royaumes)-[Ep.24]-[x264+OGG]-[JAP+FR+Sub.FR]-[Chap]-[AzF].mkv", DefaultOptions)
+	if len(e.Languages) != 2 {
+		t.Fatalf("expected 2 languages, got %d", len(e.Languages))
+	}
+	if e.Languages[0].Track != LanguageTrackUnknown {
+		t.Errorf("expected unknown track, got \"%s\"", e.Languages[0].Track)
+	}
+	if e.Languages[1].Tag() != "fr" || e.Languages[1].Track != LanguageTrackSubtitles {
+		t.Errorf("expected French subtitles, got %v", e.Languages[1])
+	}
+
+	e = Parse("Tokyo ESP - 01 [ENGSUB] PT-BR.mkv", DefaultOptions)
+	if len(e.Languages) != 2 {
+		t.Fatalf("expected 2 languages, got %d", len(e.Languages))
+	}
+	if e.Languages[0].Tag() != "en" || e.Languages[0].Track != LanguageTrackSubtitles {
+		t.Errorf("expected English subtitles, got %v", e.Languages[0])
+	}
+	if e.Languages[1].Tag() != "pt-BR" || e.Languages[1].Raw != "PT-BR" {
+		t.Errorf("expected \"pt-BR\", got %v", e.Languages[1])
+	}
+}
+
+func TestLanguageMatchLanguageToken(t *testing.T) {
+	m, found := matchLanguageToken(&token{Category: tokenCategoryUnknown, Content: "ENG-SUBS"})
+	if !found {
+		t.Fatal("expected true, got false")
+	}
+	if m.track != LanguageTrackSubtitles || len(m.languages) != 1 {
+		t.Errorf("expected one subtitle language, got %v", m)
+	}
+	_, found = matchLanguageToken(&token{Category: tokenCategoryUnknown, Content: "HARDSUB"})
+	if found {
+		t.Error("expected false, got true")
+	}
+	_, found = matchLanguageToken(&token{Category: tokenCategoryDelimiter, Content: "ENG"})
+	if found {
+		t.Error("expected false, got true")
+	}
+}
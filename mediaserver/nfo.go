@@ -0,0 +1,211 @@
+package mediaserver
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+type nfoEpisode struct {
+	XMLName   xml.Name     `xml:"episodedetails"`
+	Title     string       `xml:"title"`
+	ShowTitle string       `xml:"showtitle"`
+	Season    int          `xml:"season"`
+	Episode   int          `xml:"episode"`
+	Year      string       `xml:"year,omitempty"`
+	Source    string       `xml:"source,omitempty"`
+	FileInfo  *nfoFileInfo `xml:"fileinfo,omitempty"`
+}
+
+type nfoShow struct {
+	XMLName xml.Name `xml:"tvshow"`
+	Title   string   `xml:"title"`
+	Year    string   `xml:"year,omitempty"`
+}
+
+type nfoFileInfo struct {
+	Video    *nfoVideo     `xml:"streamdetails>video,omitempty"`
+	Audio    []nfoAudio    `xml:"streamdetails>audio,omitempty"`
+	Subtitle []nfoSubtitle `xml:"streamdetails>subtitle,omitempty"`
+}
+
+type nfoVideo struct {
+	Codec  string `xml:"codec,omitempty"`
+	Width  int    `xml:"width,omitempty"`
+	Height int    `xml:"height,omitempty"`
+}
+
+type nfoAudio struct {
+	Codec    string `xml:"codec,omitempty"`
+	Language string `xml:"language,omitempty"`
+	Channels int    `xml:"channels,omitempty"`
+}
+
+type nfoSubtitle struct {
+	Language string `xml:"language"`
+}
+
+// nfoCodecs are the codec names Kodi uses in stream details.
+var nfoCodecs = map[string]string{
+	"AV1": "av1", "DivX": "divx", "H.264": "h264", "H.265": "hevc", "MPEG-2": "mpeg2video",
+	"VC-1": "vc1", "VP9": "vp9", "WMV": "wmv3", "XviD": "xvid",
+	"AAC": "aac", "HE-AAC": "aac", "AC-3": "ac3", "E-AC-3": "eac3", "DTS": "dca", "DTS-ES": "dca",
+	"DTS-HD MA": "dtshd_ma", "TrueHD": "truehd", "FLAC": "flac", "ALAC": "alac", "PCM": "pcm",
+	"Opus": "opus", "Vorbis": "vorbis", "MP2": "mp2", "MP3": "mp3",
+}
+
+// ErrNoEpisodeNumber is returned by EpisodeNFO for a file without an episode number, e.g a movie.
+var ErrNoEpisodeNumber = errors.New("mediaserver: no episode number")
+
+// EpisodeNFO returns the Kodi "episodedetails" NFO of an episode, numbered the way the profile expects.
+// Files holding several episodes get one "episodedetails" element per episode.
+func (p *Profile) EpisodeNFO(e *anitogo.Elements) ([]byte, error) {
+	e, err := p.Elements(e)
+	if err != nil {
+		return nil, err
+	}
+	numbers := nfoEpisodeNumbers(e.EpisodeNumber)
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoEpisodeNumber, e.FileName)
+	}
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	for _, number := range numbers {
+		episode := nfoEpisode{
+			Title:     e.EpisodeTitle,
+			ShowTitle: e.AnimeTitle,
+			Episode:   number,
+			Year:      e.AnimeYear,
+			Source:    string(e.SourceType),
+			FileInfo:  newNFOFileInfo(e),
+		}
+		if len(e.AnimeSeason) > 0 {
+			episode.Season, _ = strconv.Atoi(e.AnimeSeason[0])
+		}
+		if episode.Title == "" {
+			episode.Title = "Episode " + strconv.Itoa(number)
+		}
+		out, err := xml.MarshalIndent(episode, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		b.Write(out)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// ShowNFO returns the Kodi "tvshow" NFO of the series of an episode.
+func ShowNFO(e *anitogo.Elements) ([]byte, error) {
+	out, err := xml.MarshalIndent(nfoShow{Title: e.AnimeTitle, Year: e.AnimeYear}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// Sidecar is an NFO file to write next to an organised video.
+type Sidecar struct {
+	Path string
+	Data []byte
+}
+
+// Sidecars returns the episode NFO of a video, e.g "Title S01E05.nfo" next to it, and the "tvshow.nfo"
+// of its series in the parent of its "Season" directory if there is none yet.
+func (p *Profile) Sidecars(video string, e *anitogo.Elements) ([]Sidecar, error) {
+	episode, err := p.EpisodeNFO(e)
+	if err != nil {
+		return nil, err
+	}
+	sidecars := []Sidecar{{Path: strings.TrimSuffix(video, filepath.Ext(video)) + ".nfo", Data: episode}}
+
+	dir := filepath.Dir(video)
+	if strings.HasPrefix(strings.ToLower(filepath.Base(dir)), "season ") {
+		dir = filepath.Dir(dir)
+	}
+	showPath := filepath.Join(dir, "tvshow.nfo")
+	if _, err := os.Stat(showPath); err == nil {
+		return sidecars, nil
+	}
+	e, err = p.Elements(e)
+	if err != nil {
+		return nil, err
+	}
+	show, err := ShowNFO(e)
+	if err != nil {
+		return nil, err
+	}
+	return append(sidecars, Sidecar{Path: showPath, Data: show}), nil
+}
+
+// WriteSidecars writes the sidecars of a video returned by Sidecars.
+func (p *Profile) WriteSidecars(video string, e *anitogo.Elements) error {
+	sidecars, err := p.Sidecars(video, e)
+	if err != nil {
+		return err
+	}
+	for _, sidecar := range sidecars {
+		if err := os.WriteFile(sidecar.Path, sidecar.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nfoEpisodeNumbers returns the whole episode numbers of a file. Two numbers are a range, e.g "05-07".
+func nfoEpisodeNumbers(numbers []string) []int {
+	var episodes []int
+	for _, n := range numbers {
+		if i, err := strconv.Atoi(n); err == nil {
+			episodes = append(episodes, i)
+		}
+	}
+	if len(episodes) == 2 && episodes[0] < episodes[1] {
+		first, last := episodes[0], episodes[1]
+		episodes = episodes[:0]
+		for i := first; i <= last; i++ {
+			episodes = append(episodes, i)
+		}
+	}
+	return episodes
+}
+
+func newNFOFileInfo(e *anitogo.Elements) *nfoFileInfo {
+	info := &nfoFileInfo{}
+	if e.Video != nil || e.Resolution != nil {
+		info.Video = &nfoVideo{}
+		if e.Video != nil {
+			info.Video.Codec = nfoCodecs[e.Video.Codec]
+		}
+		if e.Resolution != nil {
+			info.Video.Width, info.Video.Height = e.Resolution.Width, e.Resolution.Height
+		}
+	}
+	if e.Audio != nil {
+		for _, track := range e.Audio.Tracks {
+			audio := nfoAudio{Codec: nfoCodecs[track.Codec]}
+			if track.Language != nil {
+				audio.Language = track.Language.ISO6393
+			}
+			if main, sub, found := strings.Cut(track.Channels, "."); found || main != "" {
+				m, _ := strconv.Atoi(main)
+				s, _ := strconv.Atoi(sub)
+				audio.Channels = m + s
+			}
+			info.Audio = append(info.Audio, audio)
+		}
+	}
+	for _, lang := range e.SubtitleLanguages() {
+		info.Subtitle = append(info.Subtitle, nfoSubtitle{Language: lang.ISO6393})
+	}
+	if info.Video == nil && info.Audio == nil && info.Subtitle == nil {
+		return nil
+	}
+	return info
+}
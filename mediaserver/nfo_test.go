@@ -0,0 +1,91 @@
+package mediaserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestNFOEpisodeNFO(t *testing.T) {
+	e := anitogo.Parse("[G] Title S2 - 01 - The Episode [1080p x265 FLAC 5.1 ENG SUB] [BD].mkv", anitogo.DefaultOptions)
+	b, err := Profiles["kodi"].EpisodeNFO(e)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<episodedetails>
+  <title>The Episode</title>
+  <showtitle>Title</showtitle>
+  <season>2</season>
+  <episode>1</episode>
+  <source>BD</source>
+  <fileinfo>
+    <streamdetails>
+      <video>
+        <codec>hevc</codec>
+        <height>1080</height>
+      </video>
+      <audio>
+        <codec>flac</codec>
+        <channels>6</channels>
+      </audio>
+      <subtitle>
+        <language>eng</language>
+      </subtitle>
+    </streamdetails>
+  </fileinfo>
+</episodedetails>
+`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}
+
+func TestNFOEpisodeNFOMultiEpisode(t *testing.T) {
+	b, err := Profiles["kodi"].EpisodeNFO(anitogo.Parse("Title - 05-07.mkv", anitogo.DefaultOptions))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n := strings.Count(string(b), "<episodedetails>"); n != 3 {
+		t.Errorf("expected 3 episodes, got %d in %s", n, b)
+	}
+	if !strings.Contains(string(b), "<title>Episode 6</title>") {
+		t.Errorf("expected a default title, got %s", b)
+	}
+}
+
+func TestNFOEpisodeNFONoEpisode(t *testing.T) {
+	_, err := Profiles["kodi"].EpisodeNFO(anitogo.Parse("[G] Title Movie (2019) [1080p].mkv", anitogo.DefaultOptions))
+	if !errors.Is(err, ErrNoEpisodeNumber) {
+		t.Errorf("expected ErrNoEpisodeNumber, got %v", err)
+	}
+}
+
+func TestNFOShowNFO(t *testing.T) {
+	b, err := ShowNFO(anitogo.Parse("[G] Title (2019) - 05.mkv", anitogo.DefaultOptions))
+	expected := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<tvshow>\n  <title>Title</title>\n  <year>2019</year>\n</tvshow>\n"
+	if err != nil || string(b) != expected {
+		t.Errorf("expected %q, got %q (%v)", expected, b, err)
+	}
+}
+
+func TestNFOWriteSidecars(t *testing.T) {
+	root := t.TempDir()
+	season := filepath.Join(root, "Title", "Season 01")
+	if err := os.MkdirAll(season, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	video := filepath.Join(season, "Title S01E05.mkv")
+	if err := Profiles["kodi"].WriteSidecars(video, anitogo.Parse("Title - 05.mkv", anitogo.DefaultOptions)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, p := range []string{filepath.Join(season, "Title S01E05.nfo"), filepath.Join(root, "Title", "tvshow.nfo")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s to be written, got %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package mediaserver names files and writes NFO sidecars following the conventions of media servers
+// such as Plex, Jellyfin and Kodi.
+package mediaserver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+	"github.com/nssteinbrenner/anitogo/organise"
+)
+
+// ErrNoAbsoluteNumber is returned by absolute profiles for an episode of a later season without an
+// alternative episode number, e.g "S3 - 01", since its number in the series is unknown.
+var ErrNoAbsoluteNumber = errors.New("mediaserver: no absolute episode number")
+
+// Profile holds the naming conventions of a media server.
+type Profile struct {
+	// Name of the profile, e.g "plex".
+	Name string
+
+	// Template rendering the path of an episode relative to the library root.
+	Template *organise.Template
+
+	// If true, episodes are numbered from the first episode of the series in a single season,
+	// using the alternative episode number of files like "S3 - 01 (51)" when there is one.
+	Absolute bool
+
+	// If true, the media server reads NFO sidecars, see EpisodeNFO and ShowNFO.
+	// The organise command writes them for such profiles.
+	NFO bool
+}
+
+// Profiles are the built-in profiles by name.
+var Profiles = map[string]*Profile{
+	"plex": {
+		Name:     "plex",
+		Template: organise.MustParseTemplate("{title}< ({year})>/Season {season:02}/{title}< ({year})> - s{season:02}e{episode:02}< - {episode_title}>.{ext}"),
+	},
+	"plex-absolute": {
+		Name:     "plex-absolute",
+		Template: organise.MustParseTemplate("{title}< ({year})>/Season {season:02}/{title}< ({year})> - {episode:03}< - {episode_title}>.{ext}"),
+		Absolute: true,
+	},
+	"jellyfin": {
+		Name:     "jellyfin",
+		Template: organise.MustParseTemplate("{title}< ({year})>/Season {season:02}/{title} S{season:02}E{episode:02}< - {episode_title}>.{ext}"),
+	},
+	"jellyfin-absolute": {
+		Name:     "jellyfin-absolute",
+		Template: organise.MustParseTemplate("{title}< ({year})>/Season {season:02}/{title} - {episode:03}< - {episode_title}>.{ext}"),
+		Absolute: true,
+	},
+	"kodi": {
+		Name:     "kodi",
+		Template: organise.MustParseTemplate("{title}< ({year})>/Season {season:02}/{title} S{season:02}E{episode:02}.{ext}"),
+		NFO:      true,
+	},
+}
+
+// LookupProfile returns the built-in profile with the name.
+func LookupProfile(name string) (*Profile, error) {
+	if p, found := Profiles[strings.ToLower(name)]; found {
+		return p, nil
+	}
+	names := make([]string, 0, len(Profiles))
+	for n := range Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("mediaserver: unknown profile %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// specialAnimeTypes are the anime types media servers expect in the specials season.
+var specialAnimeTypes = []string{"OAD", "OAV", "OVA", "SP", "SPECIAL", "SPECIALS"}
+
+// IsSpecial returns true if the elements describe a special, e.g an OVA, which media servers put in "Season 00".
+func IsSpecial(e *anitogo.Elements) bool {
+	for _, t := range e.AnimeType {
+		for _, special := range specialAnimeTypes {
+			if strings.EqualFold(t, special) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Elements returns a copy of the elements numbered the way the media server expects:
+// specials are in season 0 of their series, numbered 1 if they have no number, and absolute profiles put
+// every other episode in season 1. Absolute profiles return ErrNoAbsoluteNumber for an episode of a later
+// season without an alternative episode number; use numbering.ToAbsolute to number such files first.
+func (p *Profile) Elements(e *anitogo.Elements) (*anitogo.Elements, error) {
+	c := *e
+	switch {
+	case IsSpecial(e):
+		c.AnimeSeason = []string{"0"}
+		// The type is often read as part of the title, e.g "Title OVA", which would make it another series.
+		for _, t := range e.AnimeType {
+			if title, found := strings.CutSuffix(c.AnimeTitle, " "+t); found {
+				c.AnimeTitle = title
+			}
+		}
+		if len(c.EpisodeNumber) == 0 {
+			c.EpisodeNumber = []string{"1"}
+		}
+	case p.Absolute:
+		if len(c.AnimeSeason) > 0 {
+			if len(c.EpisodeNumberAlt) > 0 {
+				c.EpisodeNumber = c.EpisodeNumberAlt
+			} else if season, err := strconv.Atoi(c.AnimeSeason[0]); err != nil || season != 1 {
+				return nil, fmt.Errorf("%w: season %s of %s", ErrNoAbsoluteNumber, c.AnimeSeason[0], e.FileName)
+			}
+		}
+		c.AnimeSeason = []string{"1"}
+	}
+	return &c, nil
+}
+
+// Path returns the path of the file relative to the library root, using "/" to separate directories.
+func (p *Profile) Path(e *anitogo.Elements) (string, error) {
+	e, err := p.Elements(e)
+	if err != nil {
+		return "", err
+	}
+	return p.Template.Render(e)
+}
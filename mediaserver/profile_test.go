@@ -0,0 +1,72 @@
+package mediaserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestProfilePath(t *testing.T) {
+	tests := []struct {
+		profile  string
+		filename string
+		expected string
+	}{
+		{"plex", "[G] Title (2019) - 05 - The Episode [1080p].mkv", "Title (2019)/Season 01/Title (2019) - s01e05 - The Episode.mkv"},
+		{"plex", "[G] Title OVA 2 [BD].mkv", "Title/Season 00/Title - s00e02.mkv"},
+		{"plex", "[G] Title OVA [BD].mkv", "Title/Season 00/Title - s00e01.mkv"},
+		{"plex-absolute", "[G] Title S2 - 01 (14) [1080p].mkv", "Title/Season 01/Title - 014.mkv"},
+		{"jellyfin", "[G] Title S2 - 01 (14) [1080p].mkv", "Title/Season 02/Title S02E01.mkv"},
+		{"jellyfin-absolute", "[G] Title - 105 [1080p].mkv", "Title/Season 01/Title - 105.mkv"},
+		{"kodi", "[G] Title - 05-07 [1080p].mkv", "Title/Season 01/Title S01E05-07.mkv"},
+	}
+	for _, test := range tests {
+		p, err := LookupProfile(test.profile)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		got, err := p.Path(anitogo.Parse(test.filename, anitogo.DefaultOptions))
+		if err != nil || got != test.expected {
+			t.Errorf("expected %q for %q with %s, got %q (%v)", test.expected, test.filename, test.profile, got, err)
+		}
+	}
+}
+
+func TestProfileElements(t *testing.T) {
+	p := Profiles["plex-absolute"]
+	_, err := p.Elements(anitogo.Parse("[G] Title S3 - 01 [1080p].mkv", anitogo.DefaultOptions))
+	if !errors.Is(err, ErrNoAbsoluteNumber) {
+		t.Errorf("expected ErrNoAbsoluteNumber, got %v", err)
+	}
+	e, err := p.Elements(anitogo.Parse("[G] Title S1 - 05 [1080p].mkv", anitogo.DefaultOptions))
+	if err != nil || e.EpisodeNumber[0] != "05" || e.AnimeSeason[0] != "1" {
+		t.Errorf("expected S1E05 to stay episode 05, got %v (%v)", e, err)
+	}
+	if _, err := Profiles["plex"].Elements(anitogo.Parse("[G] Title S3 - 01 [1080p].mkv", anitogo.DefaultOptions)); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestProfileLookupProfile(t *testing.T) {
+	if p, err := LookupProfile("Jellyfin"); err != nil || p.Name != "jellyfin" {
+		t.Errorf("expected the jellyfin profile, got %v (%v)", p, err)
+	}
+	if _, err := LookupProfile("emby"); err == nil {
+		t.Errorf("expected error for an unknown profile, got nil")
+	}
+}
+
+func TestProfileIsSpecial(t *testing.T) {
+	tests := map[string]bool{
+		"[G] Title OVA [BD].mkv":   true,
+		"[G] Title OAD 2 [BD].mkv": true,
+		"[G] Title - 05 [BD].mkv":  false,
+		"[G] Title Movie [BD].mkv": false,
+	}
+	for filename, expected := range tests {
+		if got := IsSpecial(anitogo.Parse(filename, anitogo.DefaultOptions)); got != expected {
+			t.Errorf("expected %t for %q, got %t", expected, filename, got)
+		}
+	}
+}
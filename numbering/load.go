@@ -0,0 +1,75 @@
+package numbering
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mappingsDocument is the layout of a mapping file.
+//
+// In JSON:
+//
+//	{"series": [{"title": "Kuroko no Basuke", "seasons": [{"season": 1, "episodes": 25}, {"season": 2, "episodes": 25}]}]}
+//
+// In XML:
+//
+//	<mappings>
+//	  <series title="Kuroko no Basuke">
+//	    <alias>Kuroko's Basketball</alias>
+//	    <season number="1" episodes="25"/>
+//	    <season number="2" episodes="25"/>
+//	    <episode absolute="76" season="0" episode="1"/>
+//	  </series>
+//	</mappings>
+type mappingsDocument struct {
+	XMLName xml.Name  `json:"-" xml:"mappings"`
+	Series  []*Series `json:"series" xml:"series"`
+}
+
+// LoadMappings reads a mapping file, in XML if its extension is ".xml" and in JSON otherwise.
+func LoadMappings(path string) (*Mappings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".xml" {
+		return ParseMappingsXML(data)
+	}
+	return ParseMappingsJSON(data)
+}
+
+// ParseMappingsJSON parses and validates mappings written in JSON.
+func ParseMappingsJSON(data []byte) (*Mappings, error) {
+	doc := &mappingsDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("numbering: invalid mappings: %w", err)
+	}
+	return newMappingsFromDocument(doc)
+}
+
+// ParseMappingsXML parses and validates mappings written in XML.
+func ParseMappingsXML(data []byte) (*Mappings, error) {
+	doc := &mappingsDocument{}
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("numbering: invalid mappings: %w", err)
+	}
+	return newMappingsFromDocument(doc)
+}
+
+func newMappingsFromDocument(doc *mappingsDocument) (*Mappings, error) {
+	m := NewMappings()
+	for i, s := range doc.Series {
+		if s == nil {
+			return nil, fmt.Errorf("numbering: invalid mappings: series %d is empty", i)
+		}
+		if err := s.Validate(); err != nil {
+			return nil, err
+		}
+		m.Add(s)
+	}
+	return m, nil
+}
@@ -0,0 +1,72 @@
+package numbering
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testMappingsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<mappings>
+  <series title="Kuroko no Basuke">
+    <alias>Kuroko's Basketball</alias>
+    <season number="1" episodes="25"/>
+    <season number="2" episodes="25"/>
+    <season number="3" episodes="25"/>
+    <episode absolute="76" season="0" episode="1"/>
+  </series>
+</mappings>
+`
+
+const testMappingsJSON = `{"series": [{
+  "title": "Kuroko no Basuke",
+  "aliases": ["Kuroko's Basketball"],
+  "seasons": [{"season": 1, "episodes": 25}, {"season": 2, "episodes": 25}, {"season": 3, "episodes": 25}],
+  "episodes": [{"absolute": 76, "season": 0, "episode": 1}]
+}]}`
+
+func TestLoadLoadMappings(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"mappings.xml": testMappingsXML, "mappings.json": testMappingsJSON} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		m, err := LoadMappings(path)
+		if err != nil {
+			t.Fatalf("expected no error for %s, got %v", name, err)
+		}
+		s, found := m.Series("KUROKO'S BASKETBALL")
+		if !found {
+			t.Fatalf("expected the series to be found by its alias in %s", name)
+		}
+		if len(s.Seasons) != 3 || s.Seasons[2].Episodes != 25 || len(s.Episodes) != 1 {
+			t.Errorf("expected 3 seasons and 1 episode in %s, got %+v", name, s)
+		}
+		if season, episode, err := s.Relative(76); err != nil || season != 0 || episode != 1 {
+			t.Errorf("expected S00E01 in %s, got S%02dE%02d (%v)", name, season, episode, err)
+		}
+	}
+}
+
+func TestLoadParseMappingsJSON(t *testing.T) {
+	invalid := []string{
+		`{"series": [`,
+		`{"series": [null]}`,
+		`{"series": [{"seasons": [{"season": 1, "episodes": 12}]}]}`,
+		`{"series": [{"title": "T", "seasons": [{"season": 1, "episodes": 0}, {"season": 2, "episodes": 12}]}]}`,
+		`{"series": [{"title": "T", "seasons": [{"season": 2, "episodes": 12}, {"season": 1, "episodes": 12}]}]}`,
+		`{"series": [{"title": "T", "seasons": [{"season": 1, "episodes": 12}, {"season": 2, "episodes": 12, "start": 5}]}]}`,
+	}
+	for _, data := range invalid {
+		if _, err := ParseMappingsJSON([]byte(data)); err == nil {
+			t.Errorf("expected error for %s, got nil", data)
+		}
+	}
+}
+
+func TestLoadParseMappingsXML(t *testing.T) {
+	if _, err := ParseMappingsXML([]byte("<mappings><series>")); err == nil {
+		t.Errorf("expected error for truncated XML, got nil")
+	}
+}
@@ -0,0 +1,325 @@
+// Package numbering converts episode numbers between absolute numbering, where episodes are counted
+// from the first episode of a series, and season numbering, where they are counted from the first
+// episode of each season.
+package numbering
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+var (
+	// ErrUnknownSeries is returned when no mapping has the title of a file.
+	ErrUnknownSeries = errors.New("numbering: unknown series")
+
+	// ErrOutOfRange is returned when an episode is not part of any season of a mapping.
+	ErrOutOfRange = errors.New("numbering: episode out of range")
+
+	// ErrNoEpisode is returned when a file has no whole episode number to convert.
+	ErrNoEpisode = errors.New("numbering: no episode number")
+)
+
+// Season is a season of a series.
+type Season struct {
+	// Number of the season.
+	Number int `json:"season" xml:"number,attr"`
+
+	// Number of episodes in the season. Zero for the last season of an airing series.
+	Episodes int `json:"episodes" xml:"episodes,attr"`
+
+	// Absolute number of the first episode of the season.
+	// Zero to continue from the end of the previous season, or from 1 for the first season.
+	Start int `json:"start,omitempty" xml:"start,attr,omitempty"`
+}
+
+// Episode maps an absolute episode number to a season and episode, overriding the season lengths.
+type Episode struct {
+	Absolute int `json:"absolute" xml:"absolute,attr"`
+	Season   int `json:"season" xml:"season,attr"`
+	Episode  int `json:"episode" xml:"episode,attr"`
+}
+
+// Series maps the absolute episode numbers of a series to its seasons.
+type Series struct {
+	Title    string    `json:"title" xml:"title,attr"`
+	Aliases  []string  `json:"aliases,omitempty" xml:"alias"`
+	Seasons  []Season  `json:"seasons" xml:"season"`
+	Episodes []Episode `json:"episodes,omitempty" xml:"episode"`
+}
+
+// NewSeries returns a series whose seasons have the given lengths, the first one being season 1.
+func NewSeries(title string, lengths ...int) *Series {
+	s := &Series{Title: title}
+	for i, n := range lengths {
+		s.Seasons = append(s.Seasons, Season{Number: i + 1, Episodes: n})
+	}
+	return s
+}
+
+// Validate checks that the seasons of the series are in order and do not overlap.
+func (s *Series) Validate() error {
+	if s.Title == "" {
+		return errors.New("numbering: series without a title")
+	}
+	next := 1
+	for i, season := range s.Seasons {
+		if season.Episodes < 0 || season.Start < 0 {
+			return fmt.Errorf("numbering: %s season %d: negative number of episodes or start", s.Title, season.Number)
+		}
+		if season.Episodes == 0 && i != len(s.Seasons)-1 {
+			return fmt.Errorf("numbering: %s season %d: only the last season can have an unknown number of episodes", s.Title, season.Number)
+		}
+		if i > 0 && season.Number <= s.Seasons[i-1].Number {
+			return fmt.Errorf("numbering: %s season %d: seasons must be in increasing order", s.Title, season.Number)
+		}
+		start := s.start(i)
+		if start < next {
+			return fmt.Errorf("numbering: %s season %d: starts at %d, before the end of the previous season", s.Title, season.Number, start)
+		}
+		next = start + season.Episodes
+	}
+	return nil
+}
+
+// start returns the absolute number of the first episode of the i-th season.
+func (s *Series) start(i int) int {
+	if s.Seasons[i].Start > 0 {
+		return s.Seasons[i].Start
+	}
+	if i == 0 {
+		return 1
+	}
+	return s.start(i-1) + s.Seasons[i-1].Episodes
+}
+
+// Absolute returns the absolute number of an episode of a season.
+func (s *Series) Absolute(season, episode int) (int, error) {
+	for _, e := range s.Episodes {
+		if e.Season == season && e.Episode == episode {
+			return e.Absolute, nil
+		}
+	}
+	for i, ss := range s.Seasons {
+		if ss.Number == season && episode >= 1 && (ss.Episodes == 0 || episode <= ss.Episodes) {
+			return s.start(i) + episode - 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s S%02dE%02d", ErrOutOfRange, s.Title, season, episode)
+}
+
+// Relative returns the season and episode of an absolute episode number.
+func (s *Series) Relative(absolute int) (int, int, error) {
+	for _, e := range s.Episodes {
+		if e.Absolute == absolute {
+			return e.Season, e.Episode, nil
+		}
+	}
+	for i, ss := range s.Seasons {
+		start := s.start(i)
+		if absolute >= start && (ss.Episodes == 0 || absolute < start+ss.Episodes) {
+			return ss.Number, absolute - start + 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: %s episode %d", ErrOutOfRange, s.Title, absolute)
+}
+
+// Mappings holds the series that numbers can be converted for.
+type Mappings struct {
+	series map[string]*Series
+}
+
+// NewMappings returns mappings holding the series.
+func NewMappings(series ...*Series) *Mappings {
+	m := &Mappings{series: map[string]*Series{}}
+	for _, s := range series {
+		m.Add(s)
+	}
+	return m
+}
+
+// Add adds the series under its title and aliases, replacing any series with the same title.
+func (m *Mappings) Add(s *Series) {
+	m.series[normalizeTitle(s.Title)] = s
+	for _, alias := range s.Aliases {
+		m.series[normalizeTitle(alias)] = s
+	}
+}
+
+// Series returns the series with the title or alias, ignoring case and punctuation.
+func (m *Mappings) Series(title string) (*Series, bool) {
+	s, found := m.series[normalizeTitle(title)]
+	return s, found
+}
+
+// Numbers are the season and absolute numbers of the episodes of a file.
+type Numbers struct {
+	Season int `json:"season"`
+
+	// Season-relative episode numbers, one per episode for a range like "05-07".
+	Episodes []int `json:"episodes"`
+
+	// Absolute episode numbers, in the same order as Episodes.
+	Absolute []int `json:"absolute"`
+
+	// Why the numbers in the file name disagree with the mapping. Empty if they agree.
+	Inconsistency string `json:"inconsistency,omitempty"`
+}
+
+// Resolve returns the season and absolute numbers of the episodes of a file.
+//
+// A file with a season, e.g "S3 - 01", is numbered within that season. A file without a season is
+// numbered absolutely, which is the same as season 1 for episodes of the first season. When the file has
+// an alternative number too, e.g "S3 - 01 (51)", it is checked against the mapping and any disagreement
+// is reported in Numbers.Inconsistency; the season number is trusted.
+func (m *Mappings) Resolve(e *anitogo.Elements) (*Numbers, error) {
+	s, found := m.Series(e.AnimeTitle)
+	if !found {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSeries, e.AnimeTitle)
+	}
+	episodes := expandNumbers(e.EpisodeNumber)
+	if len(episodes) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoEpisode, e.FileName)
+	}
+
+	n := &Numbers{}
+	if len(e.AnimeSeason) > 0 {
+		season, err := strconv.Atoi(e.AnimeSeason[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w in %s", ErrNoEpisode, e.FileName)
+		}
+		n.Season = season
+		for _, episode := range episodes {
+			absolute, err := s.Absolute(season, episode)
+			if err != nil {
+				return nil, err
+			}
+			n.Episodes = append(n.Episodes, episode)
+			n.Absolute = append(n.Absolute, absolute)
+		}
+		if alt := expandNumbers(e.EpisodeNumberAlt); len(alt) > 0 && !equalInts(alt, n.Absolute) {
+			n.Inconsistency = fmt.Sprintf("S%02dE%s is absolute episode %s, not %s",
+				season, joinInts(n.Episodes), joinInts(n.Absolute), joinInts(alt))
+		}
+		return n, nil
+	}
+
+	for i, absolute := range episodes {
+		season, episode, err := s.Relative(absolute)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			n.Season = season
+		} else if season != n.Season {
+			return nil, fmt.Errorf("%w: %s episodes %s span several seasons", ErrOutOfRange, s.Title, joinInts(episodes))
+		}
+		n.Episodes = append(n.Episodes, episode)
+		n.Absolute = append(n.Absolute, absolute)
+	}
+	if alt := expandNumbers(e.EpisodeNumberAlt); len(alt) > 0 && !equalInts(alt, n.Episodes) {
+		n.Inconsistency = fmt.Sprintf("absolute episode %s is S%02dE%s, not episode %s",
+			joinInts(n.Absolute), n.Season, joinInts(n.Episodes), joinInts(alt))
+	}
+	return n, nil
+}
+
+// ToAbsolute returns a copy of the elements numbered absolutely, without a season.
+func (m *Mappings) ToAbsolute(e *anitogo.Elements) (*anitogo.Elements, error) {
+	n, err := m.Resolve(e)
+	if err != nil {
+		return nil, err
+	}
+	c := *e
+	c.AnimeSeason, c.AnimeSeasonPrefix = nil, nil
+	c.EpisodeNumber = formatNumbers(n.Absolute, e.EpisodeNumber)
+	c.EpisodeNumberAlt = nil
+	return &c, nil
+}
+
+// ToSeason returns a copy of the elements numbered within their season, with the absolute
+// numbers as the alternative episode numbers.
+func (m *Mappings) ToSeason(e *anitogo.Elements) (*anitogo.Elements, error) {
+	n, err := m.Resolve(e)
+	if err != nil {
+		return nil, err
+	}
+	c := *e
+	c.AnimeSeason = []string{strconv.Itoa(n.Season)}
+	c.EpisodeNumber = formatNumbers(n.Episodes, e.EpisodeNumber)
+	c.EpisodeNumberAlt = formatNumbers(n.Absolute, nil)
+	return &c, nil
+}
+
+// expandNumbers returns the whole numbers of an episode number element. Two numbers are a range, e.g "05-07".
+// Fractional numbers like "12.5" have no place in a mapping and make it return nil.
+func expandNumbers(numbers []string) []int {
+	var ints []int
+	for _, s := range numbers {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil
+		}
+		ints = append(ints, i)
+	}
+	if len(ints) == 2 && ints[0] < ints[1] {
+		first, last := ints[0], ints[1]
+		ints = ints[:0]
+		for i := first; i <= last; i++ {
+			ints = append(ints, i)
+		}
+	}
+	return ints
+}
+
+// formatNumbers formats numbers as an episode number element, padded like the original numbers.
+// A range is written as its first and last number.
+func formatNumbers(numbers []int, original []string) []string {
+	width := 2
+	if len(original) > 0 {
+		width = len(original[0])
+	}
+	if len(numbers) > 2 {
+		numbers = []int{numbers[0], numbers[len(numbers)-1]}
+	}
+	var s []string
+	for _, n := range numbers {
+		s = append(s, fmt.Sprintf("%0*d", width, n))
+	}
+	return s
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// joinInts formats numbers as a range, e.g "5-7", or a single number.
+func joinInts(ints []int) string {
+	if len(ints) == 1 {
+		return strconv.Itoa(ints[0])
+	}
+	return fmt.Sprintf("%d-%d", ints[0], ints[len(ints)-1])
+}
+
+// normalizeTitle reduces a title to its lowercase letters and digits.
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
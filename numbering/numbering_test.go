@@ -0,0 +1,92 @@
+package numbering
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestNumberingSeries(t *testing.T) {
+	s := NewSeries("Title", 12, 13, 0)
+	s.Seasons[1].Start = 14 // A recap between the seasons takes absolute number 13.
+	if err := s.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	absolutes := map[[2]int]int{{1, 1}: 1, {1, 12}: 12, {2, 1}: 14, {2, 13}: 26, {3, 1}: 27, {3, 40}: 66}
+	for se, expected := range absolutes {
+		got, err := s.Absolute(se[0], se[1])
+		if err != nil || got != expected {
+			t.Errorf("expected S%02dE%02d to be %d, got %d (%v)", se[0], se[1], expected, got, err)
+		}
+		season, episode, err := s.Relative(expected)
+		if err != nil || season != se[0] || episode != se[1] {
+			t.Errorf("expected %d to be S%02dE%02d, got S%02dE%02d (%v)", expected, se[0], se[1], season, episode, err)
+		}
+	}
+	for _, se := range [][2]int{{1, 13}, {1, 0}, {4, 1}} {
+		if _, err := s.Absolute(se[0], se[1]); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expected %v for S%02dE%02d, got %v", ErrOutOfRange, se[0], se[1], err)
+		}
+	}
+	if _, _, err := s.Relative(13); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected %v for the gap between seasons, got %v", ErrOutOfRange, err)
+	}
+}
+
+func TestNumberingResolve(t *testing.T) {
+	m := NewMappings(NewSeries("Kuroko no Basuke", 25, 25, 25))
+	tests := []struct {
+		filename      string
+		expected      Numbers
+		inconsistency bool
+	}{
+		{"[Hatsuyuki]_Kuroko_no_Basuke_S3_-_01_(51)_[720p][10bit][619C57A0].mkv", Numbers{Season: 3, Episodes: []int{1}, Absolute: []int{51}}, false},
+		{"[Hatsuyuki]_Kuroko_no_Basuke_S3_-_01_(52)_[720p].mkv", Numbers{Season: 3, Episodes: []int{1}, Absolute: []int{51}}, true},
+		{"[Group] Kuroko no Basuke - 30 [720p].mkv", Numbers{Season: 2, Episodes: []int{5}, Absolute: []int{30}}, false},
+		{"[Group] Kuroko no Basuke S2 - 05-06 [720p].mkv", Numbers{Season: 2, Episodes: []int{5, 6}, Absolute: []int{30, 31}}, false},
+	}
+	for _, test := range tests {
+		n, err := m.Resolve(anitogo.Parse(test.filename, anitogo.DefaultOptions))
+		if err != nil {
+			t.Errorf("expected no error for %q, got %v", test.filename, err)
+			continue
+		}
+		if (n.Inconsistency != "") != test.inconsistency {
+			t.Errorf("expected inconsistency %t for %q, got %q", test.inconsistency, test.filename, n.Inconsistency)
+		}
+		n.Inconsistency = ""
+		if !reflect.DeepEqual(*n, test.expected) {
+			t.Errorf("expected %+v for %q, got %+v", test.expected, test.filename, *n)
+		}
+	}
+
+	if _, err := m.Resolve(anitogo.Parse("[Group] Other - 01.mkv", anitogo.DefaultOptions)); !errors.Is(err, ErrUnknownSeries) {
+		t.Errorf("expected %v, got %v", ErrUnknownSeries, err)
+	}
+	if _, err := m.Resolve(anitogo.Parse("[Group] Kuroko no Basuke - 80.mkv", anitogo.DefaultOptions)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected %v, got %v", ErrOutOfRange, err)
+	}
+}
+
+func TestNumberingConvert(t *testing.T) {
+	m := NewMappings(NewSeries("Title", 12, 12))
+
+	e, err := m.ToAbsolute(anitogo.Parse("[Group] Title S2 - 05 [720p].mkv", anitogo.DefaultOptions))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if e.AnimeSeason != nil || !reflect.DeepEqual(e.EpisodeNumber, []string{"17"}) {
+		t.Errorf("expected absolute episode 17 without a season, got %v %v", e.AnimeSeason, e.EpisodeNumber)
+	}
+
+	e, err = m.ToSeason(anitogo.Parse("[Group] Title - 17 [720p].mkv", anitogo.DefaultOptions))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(e.AnimeSeason, []string{"2"}) || !reflect.DeepEqual(e.EpisodeNumber, []string{"05"}) ||
+		!reflect.DeepEqual(e.EpisodeNumberAlt, []string{"17"}) {
+		t.Errorf("expected S2 episode 05 (17), got %v %v %v", e.AnimeSeason, e.EpisodeNumber, e.EpisodeNumberAlt)
+	}
+}
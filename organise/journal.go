@@ -0,0 +1,122 @@
+package organise
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// journalEntry is a line of a journal: an operation that was performed and the directories it created.
+type journalEntry struct {
+	Operation
+	CreatedDirs []string  `json:"created_dirs,omitempty"`
+	Time        time.Time `json:"time"`
+
+	// Size and modification time of the target once the operation was performed, to detect later changes.
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// newJournalEntry records the operation with the current state of its target.
+func newJournalEntry(op Operation, createdDirs []string) (journalEntry, error) {
+	info, err := os.Lstat(op.Target)
+	if err != nil {
+		return journalEntry{}, err
+	}
+	return journalEntry{Operation: op, CreatedDirs: createdDirs, Size: info.Size(), ModTime: info.ModTime().UTC()}, nil
+}
+
+type journalWriter struct {
+	f *os.File
+}
+
+func openJournal(name string) (*journalWriter, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &journalWriter{f: f}, nil
+}
+
+// write appends the entry and syncs it so the journal is complete even if the process is killed.
+func (j *journalWriter) write(entry journalEntry) error {
+	entry.Time = time.Now().UTC()
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := j.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return j.f.Sync()
+}
+
+func (j *journalWriter) Close() error {
+	return j.f.Close()
+}
+
+// Undo reverts the operations recorded in a journal, newest first. Moved files are moved back,
+// copies, links and created files are removed and the directories created by the run are removed if they are empty.
+// Operations that cannot be reverted, e.g because the target was modified or removed, are reported
+// in the returned error and the others are still reverted. The journal is removed if everything was reverted.
+func Undo(journal string) error {
+	f, err := os.Open(journal)
+	if err != nil {
+		return err
+	}
+	var entries []journalEntry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			f.Close()
+			return fmt.Errorf("organise: journal %s line %d: %w", journal, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := revert(entries[i]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removeEmptyDirs(entries[i].CreatedDirs)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return os.Remove(journal)
+}
+
+func revert(entry journalEntry) error {
+	op := entry.Operation
+	info, err := os.Lstat(op.Target)
+	if err != nil {
+		return fmt.Errorf("organise: cannot undo %s: target no longer exists", op)
+	}
+	if info.Size() != entry.Size || !info.ModTime().Equal(entry.ModTime) {
+		return fmt.Errorf("organise: cannot undo %s: target was modified", op)
+	}
+	switch op.Mode {
+	case ModeMove:
+		if exists(op.Source) {
+			return fmt.Errorf("organise: cannot undo %s: source exists again", op)
+		}
+		if _, err := mkdirAll(filepath.Dir(op.Source)); err != nil {
+			return err
+		}
+		return moveFile(op.Target, op.Source)
+	case ModeCopy, ModeHardlink, ModeSymlink, ModeCreate:
+		return os.Remove(op.Target)
+	}
+	return fmt.Errorf("organise: cannot undo %s: unknown mode", op)
+}
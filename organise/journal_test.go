@@ -0,0 +1,120 @@
+package organise
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalUndo(t *testing.T) {
+	for _, mode := range []Mode{ModeMove, ModeCopy, ModeHardlink, ModeSymlink} {
+		src, root := t.TempDir(), t.TempDir()
+		paths := writeFiles(t, src, "[Group] Show Title - 01 [1080p].mkv", "[Group] Show Title S2 - 01 [1080p].mkv")
+		journal := filepath.Join(t.TempDir(), "journal.jsonl")
+
+		o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: mode}
+		if err := Execute(o.Plan(newItems(paths)), ExecuteOptions{Journal: journal}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", mode, err)
+		}
+		if err := Undo(journal); err != nil {
+			t.Fatalf("%s: expected no error undoing, got %v", mode, err)
+		}
+		for _, p := range paths {
+			if _, err := os.Stat(p); err != nil {
+				t.Errorf("%s: expected %s to exist, got %v", mode, p, err)
+			}
+		}
+		if entries, _ := os.ReadDir(root); len(entries) != 0 {
+			t.Errorf("%s: expected the created directories to be removed, got %v", mode, entries)
+		}
+		if _, err := os.Stat(journal); !os.IsNotExist(err) {
+			t.Errorf("%s: expected the journal to be removed, got %v", mode, err)
+		}
+	}
+}
+
+func TestJournalUndoMissingTarget(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	paths := writeFiles(t, src, "[Group] Show Title - 01 [1080p].mkv")
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: ModeCopy}
+	p := o.Plan(newItems(paths))
+	if err := Execute(p, ExecuteOptions{Journal: journal}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	os.Remove(p.Operations[0].Target)
+	if err := Undo(journal); err == nil {
+		t.Errorf("expected error for missing target, got nil")
+	}
+	if _, err := os.Stat(journal); err != nil {
+		t.Errorf("expected the journal to be kept, got %v", err)
+	}
+}
+
+func TestJournalUndoRelativePaths(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, "src/[Group] Show Title - 01 [1080p].mkv")
+	journal := filepath.Join(dir, "journal.jsonl")
+
+	o := &Organiser{Root: "root", Template: MustParseTemplate(DefaultTemplate), Mode: ModeMove}
+	if err := Execute(o.Plan(newItems([]string{"src/[Group] Show Title - 01 [1080p].mkv"})), ExecuteOptions{Journal: journal}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if err := Undo(journal); err != nil {
+		t.Fatalf("expected no error undoing from another directory, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "[Group] Show Title - 01 [1080p].mkv")); err != nil {
+		t.Errorf("expected the source to be restored, got %v", err)
+	}
+}
+
+func TestJournalUndoModifiedTarget(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	paths := writeFiles(t, src, "[Group] Show Title - 01 [1080p].mkv")
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: ModeCopy}
+	p := o.Plan(newItems(paths))
+	if err := Execute(p, ExecuteOptions{Journal: journal}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := os.WriteFile(p.Operations[0].Target, []byte("edited by the user"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Undo(journal); err == nil {
+		t.Errorf("expected error for a modified target, got nil")
+	}
+	if b, err := os.ReadFile(p.Operations[0].Target); err != nil || string(b) != "edited by the user" {
+		t.Errorf("expected the modified target to be kept, got %q (%v)", b, err)
+	}
+}
+
+func TestJournalUndoCreateFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Title", "tvshow.nfo")
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+	if err := CreateFile(path, []byte("<tvshow/>"), ExecuteOptions{Journal: journal}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := CreateFile(path, []byte("<tvshow/>"), ExecuteOptions{}); err == nil {
+		t.Errorf("expected error for an existing file, got nil")
+	}
+	if err := Undo(journal); err != nil {
+		t.Fatalf("expected no error undoing, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected the file and its directory to be removed, got %v", entries)
+	}
+}
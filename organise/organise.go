@@ -0,0 +1,376 @@
+// Package organise plans and performs the moves, copies and links that turn downloaded files
+// into a folder layout rendered from their parsed elements, e.g "Title (2023)/Season 01/Title - S01E05.mkv".
+package organise
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// Mode is the way files are put at their target path.
+type Mode string
+
+const (
+	// ModeMove renames files, copying them then removing the source across file systems.
+	ModeMove Mode = "move"
+
+	// ModeCopy copies files and leaves the sources untouched.
+	ModeCopy Mode = "copy"
+
+	// ModeHardlink links the target to the source, which must be on the same file system.
+	ModeHardlink Mode = "hardlink"
+
+	// ModeSymlink creates a symbolic link to the absolute path of the source.
+	ModeSymlink Mode = "symlink"
+
+	// ModeCreate writes a new file without a source, e.g an NFO sidecar. It is used by CreateFile
+	// and is not accepted by ParseMode.
+	ModeCreate Mode = "create"
+)
+
+// ParseMode returns the Mode named by s, e.g "copy".
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case ModeMove, ModeCopy, ModeHardlink, ModeSymlink:
+		return m, nil
+	}
+	return "", fmt.Errorf("organise: unknown mode %q, expected move, copy, hardlink or symlink", s)
+}
+
+// Item is a file to organise and its parsed elements.
+type Item struct {
+	Path     string
+	Elements *anitogo.Elements
+}
+
+// NewItem parses the file at path with its parent directories as context.
+func NewItem(path string, options anitogo.Options) Item {
+	return Item{Path: path, Elements: anitogo.ParsePath(path, options)}
+}
+
+// Operation moves, copies or links a file to its target path. Plan stores absolute paths
+// so a journal can be undone from any directory.
+type Operation struct {
+	Mode   Mode   `json:"mode"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target"`
+
+	// Content of the file written by ModeCreate.
+	data []byte
+}
+
+func (op Operation) String() string {
+	if op.Mode == ModeCreate {
+		return fmt.Sprintf("%s %s", op.Mode, op.Target)
+	}
+	return fmt.Sprintf("%s %s -> %s", op.Mode, op.Source, op.Target)
+}
+
+// Conflict is a target path that cannot be used.
+type Conflict struct {
+	// Target path in conflict.
+	Target string `json:"target"`
+
+	// Files that would be put at the target path.
+	Sources []string `json:"sources"`
+
+	// Why the target path cannot be used.
+	Reason string `json:"reason"`
+}
+
+// Skipped is a file that has no target path, e.g because its title could not be parsed.
+type Skipped struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// Plan lists what an Organiser would do. Files in conflict are not part of the operations.
+type Plan struct {
+	Operations []Operation `json:"operations"`
+	Conflicts  []Conflict  `json:"conflicts,omitempty"`
+	Skipped    []Skipped   `json:"skipped,omitempty"`
+}
+
+// Organiser plans where files go and puts them there.
+type Organiser struct {
+	// Root directory of the folder layout.
+	Root string
+
+	// Template rendering the path of a file relative to Root.
+	Template *Template
+
+	// Way files are put at their target path.
+	Mode Mode
+}
+
+// Plan renders the target path of every item. Several items rendering to the same target path
+// and target paths that already exist are conflicts. Items already at their target path are skipped.
+func (o *Organiser) Plan(items []Item) *Plan {
+	p := &Plan{Operations: []Operation{}}
+	targets := map[string][]string{}
+	var order []string
+
+	root := absPath(o.Root)
+	for _, item := range items {
+		rel, err := o.Template.Render(item.Elements)
+		if err != nil {
+			p.Skipped = append(p.Skipped, Skipped{Source: item.Path, Reason: err.Error()})
+			continue
+		}
+		source := absPath(item.Path)
+		target := filepath.Join(root, filepath.FromSlash(rel))
+		if source == target {
+			p.Skipped = append(p.Skipped, Skipped{Source: item.Path, Reason: "already at its target path"})
+			continue
+		}
+		key := strings.ToLower(target) // Case-insensitive file systems would merge targets differing in case.
+		if targets[key] == nil {
+			order = append(order, key)
+		}
+		targets[key] = append(targets[key], source, target)
+	}
+
+	for _, key := range order {
+		pairs := targets[key]
+		target := pairs[1]
+		var sources []string
+		for i := 0; i < len(pairs); i += 2 {
+			sources = append(sources, pairs[i])
+		}
+		switch {
+		case len(sources) > 1:
+			p.Conflicts = append(p.Conflicts, Conflict{Target: target, Sources: sources, Reason: "several files map to the same target"})
+		case exists(target):
+			p.Conflicts = append(p.Conflicts, Conflict{Target: target, Sources: sources, Reason: "target already exists"})
+		default:
+			p.Operations = append(p.Operations, Operation{Mode: o.Mode, Source: sources[0], Target: target})
+		}
+	}
+	sort.SliceStable(p.Operations, func(i, j int) bool { return p.Operations[i].Target < p.Operations[j].Target })
+	return p
+}
+
+// ExecuteOptions configures Execute.
+type ExecuteOptions struct {
+	// If true, the operations are written to Output instead of being performed.
+	DryRun bool
+
+	// If set, every operation performed is appended to this journal so the run can be undone with Undo.
+	Journal string
+
+	// If set, every operation is written to it, one per line.
+	Output io.Writer
+}
+
+// Execute performs the operations of the plan in order and stops at the first error.
+// Operations performed before the error are kept and recorded in the journal.
+func Execute(p *Plan, options ExecuteOptions) error {
+	var journal *journalWriter
+	if options.Journal != "" && !options.DryRun {
+		var err error
+		if journal, err = openJournal(options.Journal); err != nil {
+			return err
+		}
+		defer journal.Close()
+	}
+
+	for _, op := range p.Operations {
+		if options.Output != nil {
+			prefix := ""
+			if options.DryRun {
+				prefix = "would "
+			}
+			fmt.Fprintln(options.Output, prefix+op.String())
+		}
+		if options.DryRun {
+			continue
+		}
+		createdDirs, err := mkdirAll(filepath.Dir(op.Target))
+		if err != nil {
+			return err
+		}
+		if err := perform(op); err != nil {
+			removeEmptyDirs(createdDirs)
+			return err
+		}
+		if journal != nil {
+			entry, err := newJournalEntry(op, createdDirs)
+			if err != nil {
+				return err
+			}
+			if err := journal.write(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateFile writes a new file at path, e.g a sidecar of an organised file, and records it in the journal
+// so Undo removes it. An existing file is not overwritten.
+func CreateFile(path string, data []byte, options ExecuteOptions) error {
+	return Execute(&Plan{Operations: []Operation{{Mode: ModeCreate, Target: absPath(path), data: data}}}, options)
+}
+
+// perform puts the file at its target path. A target created since the plan was made is never overwritten.
+func perform(op Operation) error {
+	var err error
+	switch op.Mode {
+	case ModeMove:
+		err = moveFile(op.Source, op.Target)
+	case ModeCopy:
+		err = copyFile(op.Source, op.Target)
+	case ModeHardlink:
+		err = os.Link(op.Source, op.Target)
+	case ModeSymlink:
+		err = os.Symlink(absPath(op.Source), op.Target)
+	case ModeCreate:
+		err = writeFile(op.Target, op.data)
+	default:
+		return fmt.Errorf("organise: unknown mode %q", op.Mode)
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("organise: %s already exists", op.Target)
+	}
+	return err
+}
+
+// moveFile moves the file, and copies then removes it when it is moved to another file system.
+// The copy is removed if the source cannot be, so the file is never left at both paths.
+func moveFile(source, target string) error {
+	err := placeFile(source, target)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyFile(source, target); err != nil {
+		return err
+	}
+	if err := os.Remove(source); err != nil {
+		os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+// placeFile renames from to without overwriting to: it links to then removes from, so an existing target fails
+// with an error matching fs.ErrExist. File systems without hard links fall back to a rename after checking that
+// the target does not exist.
+func placeFile(from, to string) error {
+	err := os.Link(from, to)
+	switch {
+	case err == nil:
+		if err := os.Remove(from); err != nil {
+			os.Remove(to)
+			return err
+		}
+		return nil
+	case errors.Is(err, fs.ErrExist) || errors.Is(err, syscall.EXDEV):
+		return err
+	case exists(to):
+		return &os.LinkError{Op: "rename", Old: from, New: to, Err: fs.ErrExist}
+	}
+	return os.Rename(from, to)
+}
+
+// copyFile copies the file through a temporary file so the target never holds a partial copy.
+// An existing target is not overwritten.
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(target), ".organise-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Chmod(info.Mode().Perm()); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(out.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	return placeFile(out.Name(), target)
+}
+
+// writeFile writes data through a temporary file so the target never holds a partial file.
+// An existing target is not overwritten.
+func writeFile(target string, data []byte) error {
+	out, err := os.CreateTemp(filepath.Dir(target), ".organise-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Chmod(0o644); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return placeFile(out.Name(), target)
+}
+
+// mkdirAll creates dir and its missing parents and returns the directories it created, deepest first.
+func mkdirAll(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; !exists(d); d = filepath.Dir(d) {
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// removeEmptyDirs removes the directories in order and stops at the first one that is not empty.
+func removeEmptyDirs(dirs []string) {
+	for _, d := range dirs {
+		if os.Remove(d) != nil {
+			return
+		}
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// absPath returns the absolute path of path, or path itself if the working directory is unknown.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,152 @@
+package organise
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// writeFiles creates the files under dir with their name as content and returns their paths.
+func writeFiles(t *testing.T, dir string, names ...string) []string {
+	t.Helper()
+	var paths []string
+	for _, name := range names {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func newItems(paths []string) []Item {
+	var items []Item
+	for _, p := range paths {
+		items = append(items, NewItem(p, anitogo.DefaultOptions))
+	}
+	return items
+}
+
+func TestOrganiseParseMode(t *testing.T) {
+	if m, err := ParseMode("Hardlink"); err != nil || m != ModeHardlink {
+		t.Errorf("expected %q, got %q (%v)", ModeHardlink, m, err)
+	}
+	if _, err := ParseMode("teleport"); err == nil {
+		t.Errorf("expected error for unknown mode, got nil")
+	}
+}
+
+func TestOrganisePlan(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	paths := writeFiles(t, src,
+		"[Group] Show Title - 01 [1080p].mkv",
+		"[Group] Show Title - 02 [1080p].mkv",
+		"[Other] Show Title - 02 [720p].mkv",
+		"[Group] Show Title - 03 [1080p].mkv",
+		"random.mkv",
+	)
+	writeFiles(t, root, "Show Title/Season 01/Show Title - S01E03.mkv")
+
+	o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: ModeMove}
+	p := o.Plan(newItems(paths))
+
+	if len(p.Operations) != 1 {
+		t.Fatalf("expected 1 operation, got %v", p.Operations)
+	}
+	expected := filepath.Join(root, "Show Title", "Season 01", "Show Title - S01E01.mkv")
+	if p.Operations[0].Source != paths[0] || p.Operations[0].Target != expected || !filepath.IsAbs(p.Operations[0].Source) {
+		t.Errorf("expected %s -> %s, got %s", paths[0], expected, p.Operations[0])
+	}
+	if len(p.Conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %v", p.Conflicts)
+	}
+	if len(p.Conflicts[0].Sources) != 2 || p.Conflicts[0].Reason != "several files map to the same target" {
+		t.Errorf("expected 2 sources mapping to the same target, got %v", p.Conflicts[0])
+	}
+	if p.Conflicts[1].Reason != "target already exists" {
+		t.Errorf("expected existing target, got %v", p.Conflicts[1])
+	}
+	if len(p.Skipped) != 1 || p.Skipped[0].Source != paths[4] {
+		t.Errorf("expected %s to be skipped, got %v", paths[4], p.Skipped)
+	}
+}
+
+func TestOrganiseExecute(t *testing.T) {
+	for _, mode := range []Mode{ModeMove, ModeCopy, ModeHardlink, ModeSymlink} {
+		src, root := t.TempDir(), t.TempDir()
+		paths := writeFiles(t, src, "[Group] Show Title - 01 [1080p].mkv")
+		target := filepath.Join(root, "Show Title", "Season 01", "Show Title - S01E01.mkv")
+
+		o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: mode}
+		if err := Execute(o.Plan(newItems(paths)), ExecuteOptions{}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", mode, err)
+		}
+		b, err := os.ReadFile(target)
+		if err != nil || string(b) != "[Group] Show Title - 01 [1080p].mkv" {
+			t.Errorf("%s: expected target with the source content, got %q (%v)", mode, b, err)
+		}
+		if _, err := os.Stat(paths[0]); (mode == ModeMove) != os.IsNotExist(err) {
+			t.Errorf("%s: unexpected source state: %v", mode, err)
+		}
+		if info, err := os.Lstat(target); err == nil && (mode == ModeSymlink) != (info.Mode()&os.ModeSymlink != 0) {
+			t.Errorf("%s: unexpected target mode %v", mode, info.Mode())
+		}
+	}
+}
+
+func TestOrganiseMoveFile(t *testing.T) {
+	dir := t.TempDir()
+	source, target := filepath.Join(dir, "missing.mkv"), filepath.Join(dir, "target.mkv")
+	if err := moveFile(source, target); err == nil {
+		t.Errorf("expected error for a missing source, got nil")
+	}
+	if exists(target) {
+		t.Errorf("expected no copy at %s", target)
+	}
+}
+
+func TestOrganisePerform(t *testing.T) {
+	for _, mode := range []Mode{ModeMove, ModeCopy, ModeHardlink, ModeSymlink} {
+		dir := t.TempDir()
+		paths := writeFiles(t, dir, "source.mkv", "target.mkv")
+		err := perform(Operation{Mode: mode, Source: paths[0], Target: paths[1]})
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("%s: expected an existing target error, got %v", mode, err)
+		}
+		if b, err := os.ReadFile(paths[1]); err != nil || string(b) != "target.mkv" {
+			t.Errorf("%s: expected the target to be kept, got %q (%v)", mode, b, err)
+		}
+		if !exists(paths[0]) {
+			t.Errorf("%s: expected the source to be kept", mode)
+		}
+	}
+}
+
+func TestOrganiseExecuteDryRun(t *testing.T) {
+	src, root := t.TempDir(), t.TempDir()
+	paths := writeFiles(t, src, "[Group] Show Title - 01 [1080p].mkv")
+
+	o := &Organiser{Root: root, Template: MustParseTemplate(DefaultTemplate), Mode: ModeMove}
+	var out bytes.Buffer
+	if err := Execute(o.Plan(newItems(paths)), ExecuteOptions{DryRun: true, Output: &out, Journal: filepath.Join(root, "journal")}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "would move "+paths[0]+" -> ") {
+		t.Errorf("expected dry-run output, got %q", out.String())
+	}
+	entries, _ := os.ReadDir(root)
+	if len(entries) != 0 {
+		t.Errorf("expected dry-run to leave the root empty, got %v", entries)
+	}
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Errorf("expected source to be kept, got %v", err)
+	}
+}
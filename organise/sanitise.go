@@ -0,0 +1,48 @@
+package organise
+
+import (
+	"strings"
+	"unicode"
+)
+
+// windowsReservedNames cannot be used as file names on Windows, with or without an extension.
+var windowsReservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// sanitiseValue makes a placeholder value safe to use in a file name. Colons become dashes,
+// e.g "Re:Zero" is "Re-Zero", other characters that are invalid on Windows and control characters are removed,
+// and slashes are replaced so a value never creates a directory.
+func sanitiseValue(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r == ':':
+			b.WriteRune('-')
+		case r == '/' || r == '\\':
+			b.WriteRune(' ')
+		case strings.ContainsRune(`<>"|?*`, r), unicode.IsControl(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// sanitiseComponent makes a rendered path component valid on every common file system:
+// leading and trailing spaces and trailing dots are removed and reserved names are prefixed with "_".
+func sanitiseComponent(component string) string {
+	component = strings.TrimRight(strings.TrimSpace(component), ". ")
+	if component == "" {
+		return ""
+	}
+	base, _, _ := strings.Cut(component, ".")
+	for _, reserved := range windowsReservedNames {
+		if strings.EqualFold(base, reserved) {
+			return "_" + component
+		}
+	}
+	return component
+}
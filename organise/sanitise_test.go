@@ -0,0 +1,31 @@
+package organise
+
+import "testing"
+
+func TestSanitiseSanitiseValue(t *testing.T) {
+	tests := map[string]string{
+		"Re:Zero":          "Re-Zero",
+		"Fate/Zero":        "Fate Zero",
+		`What?! "Really"*`: "What! Really",
+		"A  |  B":          "A B",
+	}
+	for value, expected := range tests {
+		if got := sanitiseValue(value); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestSanitiseSanitiseComponent(t *testing.T) {
+	tests := map[string]string{
+		" Title. ": "Title",
+		"CON":      "_CON",
+		"aux.mkv":  "_aux.mkv",
+		"Console":  "Console",
+	}
+	for value, expected := range tests {
+		if got := sanitiseComponent(value); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package organise
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// DefaultTemplate lays files out as "Title (Year)/Season 01/Title - S01E05.mkv".
+const DefaultTemplate = "{title}< ({year})>/Season {season:02}/{title} - S{season:02}E{episode:02}<v{version}>.{ext}"
+
+// Template renders the target path of a file from its elements.
+//
+// Placeholders are written as {name}, or {name:0N} to pad numbers with zeros to N digits.
+// Text between < and > is only kept if every placeholder inside it has a value,
+// e.g "< ({year})>" disappears for files without a year. "/" separates directories.
+//
+// The placeholders are:
+//
+//	title          anime title
+//	year           anime year
+//	season         season number, 1 for files with an episode number but no season
+//	episode        episode number, e.g "05" or "05-06" for files holding two episodes
+//	episode_title  episode title
+//	group          release group
+//	resolution     resolution label, e.g "1080p"
+//	source         source type, e.g "BD"
+//	version        release version, e.g "2" for "05v2", empty for the first version
+//	checksum       CRC32 checksum
+//	edition        editions joined with spaces
+//	ext            file extension
+//	name           original file name without its extension
+type Template struct {
+	text  string
+	nodes []templateNode
+}
+
+type templateNode struct {
+	literal string
+	field   string
+	width   int
+
+	// Nodes of an optional section between < and >.
+	optional []templateNode
+}
+
+var templateFields = map[string]func(*anitogo.Elements) string{
+	"title": func(e *anitogo.Elements) string { return e.AnimeTitle },
+	"year":  func(e *anitogo.Elements) string { return e.AnimeYear },
+	"season": func(e *anitogo.Elements) string {
+		if len(e.AnimeSeason) > 0 {
+			return e.AnimeSeason[0]
+		}
+		if len(e.EpisodeNumber) > 0 {
+			return "1"
+		}
+		return ""
+	},
+	"episode":       func(e *anitogo.Elements) string { return strings.Join(e.EpisodeNumber, "-") },
+	"episode_title": func(e *anitogo.Elements) string { return e.EpisodeTitle },
+	"group":         func(e *anitogo.Elements) string { return e.ReleaseGroup },
+	"resolution": func(e *anitogo.Elements) string {
+		if e.Resolution != nil {
+			return e.Resolution.Label
+		}
+		return e.VideoResolution
+	},
+	"source": func(e *anitogo.Elements) string { return string(e.SourceType) },
+	"version": func(e *anitogo.Elements) string {
+		for _, v := range e.ReleaseVersion {
+			if v != "0" && v != "1" {
+				return v
+			}
+		}
+		return ""
+	},
+	"checksum": func(e *anitogo.Elements) string { return e.FileChecksum },
+	"edition":  func(e *anitogo.Elements) string { return strings.Join(e.Edition, " ") },
+	"ext":      func(e *anitogo.Elements) string { return strings.ToLower(e.FileExtension) },
+	"name": func(e *anitogo.Elements) string {
+		name := path.Base(strings.ReplaceAll(e.FileName, "\\", "/"))
+		if e.FileExtension != "" {
+			name = strings.TrimSuffix(name, "."+e.FileExtension)
+		}
+		return name
+	},
+}
+
+// ParseTemplate parses a naming template. See Template for the syntax.
+func ParseTemplate(text string) (*Template, error) {
+	nodes, rest, err := parseTemplateNodes(text, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != len(text) {
+		return nil, fmt.Errorf("organise: template: unexpected \">\" at position %d", rest)
+	}
+	return &Template{text: text, nodes: nodes}, nil
+}
+
+// MustParseTemplate is like ParseTemplate but panics if the template is invalid.
+func MustParseTemplate(text string) *Template {
+	t, err := ParseTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (t *Template) String() string {
+	return t.text
+}
+
+func parseTemplateNodes(text string, pos int, optional bool) ([]templateNode, int, error) {
+	var nodes []templateNode
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			nodes = append(nodes, templateNode{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for pos < len(text) {
+		switch c := text[pos]; c {
+		case '{':
+			end := strings.IndexByte(text[pos:], '}')
+			if end == -1 {
+				return nil, pos, fmt.Errorf("organise: template: unterminated placeholder at position %d", pos)
+			}
+			name, format, _ := strings.Cut(text[pos+1:pos+end], ":")
+			if templateFields[name] == nil {
+				return nil, pos, fmt.Errorf("organise: template: unknown placeholder %q at position %d", name, pos)
+			}
+			node := templateNode{field: name}
+			if format != "" {
+				width, err := strconv.Atoi(format)
+				if err != nil || width <= 0 || !strings.HasPrefix(format, "0") {
+					return nil, pos, fmt.Errorf("organise: template: invalid format %q at position %d, expected e.g \"02\"", format, pos)
+				}
+				node.width = width
+			}
+			flush()
+			nodes = append(nodes, node)
+			pos += end + 1
+		case '<':
+			if optional {
+				return nil, pos, fmt.Errorf("organise: template: nested \"<\" at position %d", pos)
+			}
+			inner, end, err := parseTemplateNodes(text, pos+1, true)
+			if err != nil {
+				return nil, pos, err
+			}
+			if end >= len(text) {
+				return nil, pos, fmt.Errorf("organise: template: unterminated \"<\" at position %d", pos)
+			}
+			flush()
+			nodes = append(nodes, templateNode{optional: inner})
+			pos = end + 1
+		case '>':
+			flush()
+			return nodes, pos, nil
+		case '}':
+			return nil, pos, fmt.Errorf("organise: template: unexpected \"}\" at position %d", pos)
+		default:
+			literal.WriteByte(c)
+			pos++
+		}
+	}
+	flush()
+	return nodes, pos, nil
+}
+
+// Render returns the target path of a file with the given elements, using "/" to separate directories.
+// Values are sanitised so they are valid in file names, and a missing required placeholder is an error.
+func (t *Template) Render(e *anitogo.Elements) (string, error) {
+	rendered, missing := renderTemplateNodes(t.nodes, e)
+	if missing != "" {
+		return "", fmt.Errorf("organise: %s has no %s", e.FileName, missing)
+	}
+	var parts []string
+	for _, part := range strings.Split(rendered, "/") {
+		if part = sanitiseComponent(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("organise: %s renders to an empty path", e.FileName)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// renderTemplateNodes renders the nodes and returns the first placeholder without a value, if any.
+func renderTemplateNodes(nodes []templateNode, e *anitogo.Elements) (string, string) {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch {
+		case n.optional != nil:
+			if s, missing := renderTemplateNodes(n.optional, e); missing == "" {
+				b.WriteString(s)
+			}
+		case n.field != "":
+			value := sanitiseValue(templateFields[n.field](e))
+			if value == "" {
+				return "", n.field
+			}
+			b.WriteString(padNumbers(value, n.width))
+		default:
+			b.WriteString(n.literal)
+		}
+	}
+	return b.String(), ""
+}
+
+// padNumbers pads the integer part of every number in value, e.g "5-6" is "05-06" with a width of 2.
+func padNumbers(value string, width int) string {
+	if width == 0 {
+		return value
+	}
+	parts := strings.Split(value, "-")
+	for i, p := range parts {
+		integer, fraction, _ := strings.Cut(p, ".")
+		if _, err := strconv.Atoi(integer); err != nil {
+			return value
+		}
+		integer = strings.TrimLeft(integer, "0")
+		if len(integer) < width {
+			integer = strings.Repeat("0", width-len(integer)) + integer
+		}
+		if fraction != "" {
+			integer += "." + fraction
+		}
+		parts[i] = integer
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,56 @@
+package organise
+
+import (
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tests := []struct {
+		template string
+		filename string
+		expected string
+	}{
+		{DefaultTemplate, "[Group] Show Title - 05 [1080p].mkv", "Show Title/Season 01/Show Title - S01E05.mkv"},
+		{DefaultTemplate, "[Group] Show Title S2 - 05v2 [1080p].MKV", "Show Title/Season 02/Show Title - S02E05v2.mkv"},
+		{DefaultTemplate, "[Group] Show Title (2019) - 05-06 [1080p].mkv", "Show Title (2019)/Season 01/Show Title - S01E05-06.mkv"},
+		{"{group}/{title} - {episode:03} [{resolution}].{ext}", "[Group] Re:Zero - 7 [720p].mkv", "Group/Re-Zero - 007 [720p].mkv"},
+		{"{title}<[{checksum}]>.{ext}", "[Group] Show Title - 05 [ABCD1234].mkv", "Show Title[ABCD1234].mkv"},
+	}
+	for _, test := range tests {
+		e := anitogo.Parse(test.filename, anitogo.DefaultOptions)
+		got, err := MustParseTemplate(test.template).Render(e)
+		if err != nil {
+			t.Errorf("expected no error rendering %q, got %v", test.filename, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
+
+func TestTemplateRenderMissing(t *testing.T) {
+	e := anitogo.Parse("[Group] Show Title - 05.mkv", anitogo.DefaultOptions)
+	if _, err := MustParseTemplate("{title} - {year}.{ext}").Render(e); err == nil {
+		t.Errorf("expected error for missing year, got nil")
+	}
+}
+
+func TestTemplateParseTemplate(t *testing.T) {
+	for _, text := range []string{"{title", "{unknown}", "{episode:2}", "<{title}", "{title}>", "<<{title}>>", "{title}}"} {
+		if _, err := ParseTemplate(text); err == nil {
+			t.Errorf("expected error for %q, got nil", text)
+		}
+	}
+}
+
+func TestTemplatePadNumbers(t *testing.T) {
+	tests := map[string]string{"5": "05", "5-6": "05-06", "12.5": "12.5", "007": "07", "123": "123", "SP": "SP"}
+	for value, expected := range tests {
+		if got := padNumbers(value, 2); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
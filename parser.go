@@ -8,6 +8,9 @@ import (
 
 type parser struct {
 	tokenizer *tokenizer
+
+	// If true, the name is a directory, whose title can run to the end of the name, e.g "Some Title".
+	directory bool
 }
 
 func newParser(tkz *tokenizer) *parser {
@@ -18,7 +21,14 @@ func newParser(tkz *tokenizer) *parser {
 }
 
 func (p *parser) parse() {
+	p.searchForEdition()
 	p.searchForKeywords()
+	p.searchForLanguages()
+	p.searchForVideoTerms()
+	p.searchForAudioTerms()
+	p.searchForStreamingService()
+	p.searchForSource()
+	p.searchForRevision()
 	p.searchForIsolatedNumbers()
 	if p.tokenizer.options.ParseEpisodeNumber {
 		p.searchForEpisodeNumber()
@@ -31,6 +41,15 @@ func (p *parser) parse() {
 		p.searchForEpisodeTitle()
 	}
 	p.validateElements()
+	p.buildResolution()
+	p.buildVideo()
+	p.buildAudio()
+	p.buildSourceType()
+	p.buildRevision()
+	p.buildEdition()
+	p.buildCensorship()
+	p.buildBatch()
+	p.buildCanonical()
 }
 
 func (p *parser) searchForKeywords() {
@@ -110,7 +129,7 @@ func (p *parser) searchForIsolatedNumbers() {
 			}
 		}
 
-		if n == 480 || n == 720 || n == 1080 {
+		if n == 480 || n == 720 || n == 1080 || n == 1440 || n == 2160 || n == 4320 {
 			if !p.tokenizer.elements.contains(elementCategoryVideoResolution) {
 				p.tokenizer.elements.insert(elementCategoryVideoResolution, tkn.Content)
 				tkn.Category = tokenCategoryIdentifier
@@ -195,7 +214,19 @@ func (p *parser) searchForAnimeTitle() {
 	if enclosedTitle {
 		targetFlag = tokenFlagsBracket
 	}
-	tokenEnd, _ := p.tokenizer.tokens.findNext(*tokenBegin, tokenFlagsIdentifier|targetFlag)
+	tokenEnd, found := p.tokenizer.tokens.findNext(*tokenBegin, tokenFlagsIdentifier|targetFlag)
+	if !found && !enclosedTitle && p.directory {
+		// The title runs to the end of the directory name, e.g "Some Title".
+		// Lone numbers and keywords, e.g "1" or "NCOP", are not taken as a title.
+		tokenEnd, _ = p.tokenizer.tokens.get(len(*p.tokenizer.tokens) - 1)
+		for _, tkn := range p.tokenizer.tokens.getList(tokenFlagsUnknown, tokenBegin, tokenEnd) {
+			if _, isKeyword := p.tokenizer.keywordManager.findWithoutCategory(p.tokenizer.keywordManager.normalize(tkn.Content)); !isKeyword && !isNumeric(tkn.Content) {
+				p.buildElement(elementCategoryAnimeTitle, tokenBegin, tokenEnd, false)
+				break
+			}
+		}
+		return
+	}
 	if !enclosedTitle {
 		lastBracket := tokenEnd
 		bracketOpen := false
@@ -132,7 +132,7 @@ func isHexadecimalString(str string) bool {
 }
 
 func isResolution(str string) bool {
-	pattern := "\\d{3,4}([pP]|([xX\u00D7]\\d{3,4}))$"
+	pattern := "\\d{3,4}([pPiI]|([xX\u00D7]\\d{3,4}))$"
 	found, _ := regexp.Match(pattern, []byte(str))
 	return found
 }
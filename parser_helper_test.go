@@ -156,6 +156,14 @@ func TestParserHelperIsResolution(t *testing.T) {
 	if !ret {
 		t.Error("expected true, got false")
 	}
+	ret = isResolution("1080i")
+	if !ret {
+		t.Error("expected true, got false")
+	}
+	ret = isResolution("1920\u00D71080")
+	if !ret {
+		t.Error("expected true, got false")
+	}
 }
 
 func TestParserHelperGetNumberFromOrdinal(t *testing.T) {
@@ -0,0 +1,108 @@
+package anitogo
+
+import (
+	"strings"
+	"sync"
+)
+
+// pathGenericDirectories are directory names that describe the content of a directory rather than the anime.
+var pathGenericDirectories = []string{
+	"ATTACHMENTS", "BONUS", "CDS", "EXTRA", "EXTRAS", "FONTS", "MENU", "MENUS", "NC", "NCED", "NCOP",
+	"OST", "SAMPLE", "SAMPLES", "SCANS", "SPECIALS", "SPS", "SUBS", "SUBTITLES",
+}
+
+// pathInheritedCategories are the elements a file inherits from its directories when its name does not have them.
+var pathInheritedCategories = []elementCategory{
+	elementCategoryAnimeTitle,
+	elementCategoryAnimeSeason,
+	elementCategoryAnimeYear,
+	elementCategoryReleaseGroup,
+	elementCategorySource,
+	elementCategoryVideoResolution,
+}
+
+// ParsePath returns a pointer to an Elements struct created by parsing the file name at the end of path
+// with the specified options. Both "/" and "\" separate directories.
+//
+// Elements missing from the file name are taken from the nearest parent directory that has them.
+// In "[Group] Title (BD 1080p)/Season 2/05.mkv", the file is episode 5 of season 2 of "Title",
+// released by "Group" from a 1080p Blu-ray. Only the anime title, season, year, release group,
+// source and video resolution are inherited. Directories like "Extras" or "Fonts" are skipped.
+func ParsePath(path string, options Options) *Elements {
+	parts := splitPath(path)
+	if len(parts) == 0 {
+		return &Elements{}
+	}
+	e := Parse(parts[len(parts)-1], options)
+	name := strings.TrimSuffix(parts[len(parts)-1], "."+e.FileExtension)
+	if options.ParseEpisodeNumber && !e.contains(elementCategoryEpisodeNumber) && isNumeric(name) {
+		// A file named after its episode number alone, e.g "05.mkv", takes its title from the directories.
+		e.insert(elementCategoryEpisodeNumber, name)
+	}
+
+	dirOptions := options
+	dirOptions.ParseFileExtension = false
+	for i := len(parts) - 2; i >= 0; i-- {
+		dir := parts[i]
+		if checkInList(pathGenericDirectories, strings.ToUpper(dir)) || strings.HasSuffix(dir, ":") {
+			continue
+		}
+		d := parse(dir, dirOptions, true)
+		for _, cat := range pathInheritedCategories {
+			if e.contains(cat) || !d.contains(cat) {
+				continue
+			}
+			for _, v := range d.get(cat) {
+				e.insert(cat, v)
+			}
+			name := elementCategoryNames[cat]
+			if values, found := d.Canonical[name]; found {
+				if e.Canonical == nil {
+					e.Canonical = map[string][]string{}
+				}
+				e.Canonical[name] = values
+			}
+			switch cat {
+			case elementCategoryAnimeSeason:
+				e.AnimeSeasonPrefix = d.AnimeSeasonPrefix
+			case elementCategorySource:
+				e.SourceType = d.SourceType
+			case elementCategoryVideoResolution:
+				e.Resolution = d.Resolution
+			}
+		}
+	}
+	return e
+}
+
+// splitPath splits a path on "/" and "\" and drops the empty, "." and ".." parts.
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if p != "." && p != ".." {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+var (
+	sharedKeywordManager     *keywordManager
+	sharedKeywordManagerOnce sync.Once
+)
+
+// getSharedKeywordManager returns a keyword manager shared by the functions that only read keywords.
+func getSharedKeywordManager() *keywordManager {
+	sharedKeywordManagerOnce.Do(func() {
+		sharedKeywordManager = newKeywordManager()
+	})
+	return sharedKeywordManager
+}
+
+// IsVideoExtension returns true if ext, with or without the leading ".", is the extension of a video file, e.g "mkv".
+// Extensions of audio, subtitle and archive files, e.g "mka", "ass" or "zip", return false.
+func IsVideoExtension(ext string) bool {
+	kwm := getSharedKeywordManager()
+	kd, found := kwm.find(kwm.normalize(strings.TrimPrefix(ext, ".")), elementCategoryFileExtension)
+	return found && kd.options.valid
+}
@@ -0,0 +1,58 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestPathParsePath(t *testing.T) {
+	e := ParsePath("/anime/[Group] Title (BD 1080p)/Season 2/05.mkv", DefaultOptions)
+	if e.AnimeTitle != "Title" || e.ReleaseGroup != "Group" {
+		t.Errorf("expected \"Title\" by \"Group\", got \"%s\" by \"%s\"", e.AnimeTitle, e.ReleaseGroup)
+	}
+	if len(e.AnimeSeason) != 1 || e.AnimeSeason[0] != "2" {
+		t.Errorf("expected season 2, got %v", e.AnimeSeason)
+	}
+	if len(e.EpisodeNumber) != 1 || e.EpisodeNumber[0] != "05" {
+		t.Errorf("expected episode 05, got %v", e.EpisodeNumber)
+	}
+	if e.SourceType != SourceTypeBD || e.Resolution == nil || e.Resolution.Height != 1080 {
+		t.Errorf("expected a 1080p Blu-ray, got %s and %v", e.SourceType, e.Resolution)
+	}
+	if e.FileName != "05.mkv" {
+		t.Errorf("expected \"05.mkv\", got \"%s\"", e.FileName)
+	}
+
+	e = ParsePath(`D:\Anime\Title\Extras\[Other] Other Title - 01 [720p].mkv`, DefaultOptions)
+	if e.AnimeTitle != "Other Title" || e.ReleaseGroup != "Other" || e.VideoResolution != "720p" {
+		t.Errorf("expected the file name to take precedence, got %v", e)
+	}
+
+	e = ParsePath("Title/Extras/NCOP.mkv", DefaultOptions)
+	if e.AnimeTitle != "Title" {
+		t.Errorf("expected \"Title\", got \"%s\"", e.AnimeTitle)
+	}
+
+	e = ParsePath("Some Title/05.mkv", DefaultOptions)
+	if e.AnimeTitle != "Some Title" {
+		t.Errorf("expected \"Some Title\", got \"%s\"", e.AnimeTitle)
+	}
+
+	e = ParsePath("Some Title/1/05.mkv", DefaultOptions)
+	if e.AnimeTitle != "Some Title" {
+		t.Errorf("expected the lone number to be skipped, got \"%s\"", e.AnimeTitle)
+	}
+
+	e = ParsePath("", DefaultOptions)
+	if e.AnimeTitle != "" {
+		t.Errorf("expected empty elements, got %v", e)
+	}
+}
+
+func TestPathIsVideoExtension(t *testing.T) {
+	testCases := map[string]bool{"mkv": true, ".MP4": true, "mka": false, "ass": false, "txt": false}
+	for ext, expected := range testCases {
+		if got := IsVideoExtension(ext); got != expected {
+			t.Errorf("%s: expected %t, got %t", ext, expected, got)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package anitogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleType is the condition a quality profile rule checks.
+type RuleType string
+
+const (
+	// RuleTypeGroup matches releases from one of the groups in Values.
+	RuleTypeGroup RuleType = "group"
+
+	// RuleTypeMinResolution matches releases with a resolution of at least the one in Values, e.g "1080p".
+	RuleTypeMinResolution RuleType = "min_resolution"
+
+	// RuleTypeTerm matches releases with any of the terms in Values, e.g "HEVC" or "HARDSUB".
+	// Terms are compared ignoring case against every element except the titles and the file name,
+	// and against the canonical values, so "H.265" also matches "x265".
+	RuleTypeTerm RuleType = "term"
+
+	// RuleTypeSource matches releases ripped from one of the source types in Values, e.g "BD".
+	RuleTypeSource RuleType = "source"
+
+	// RuleTypeChecksum matches releases with a CRC32 checksum in the filename.
+	RuleTypeChecksum RuleType = "checksum"
+)
+
+// Rule is a weighted condition of a quality profile.
+type Rule struct {
+	// Name of the rule shown in the score breakdown. Defaults to the rule type.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Condition checked by the rule.
+	Type RuleType `json:"type" yaml:"type"`
+
+	// Values the condition is checked against. Their meaning depends on Type.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+
+	// Points added to the score when the rule matches. Negative weights penalise a match.
+	Weight int `json:"weight,omitempty" yaml:"weight,omitempty"`
+
+	// If true, releases that do not match the rule are rejected.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// If true, releases that match the rule are rejected.
+	Forbidden bool `json:"forbidden,omitempty" yaml:"forbidden,omitempty"`
+}
+
+// Profile is a set of weighted rules used to rank releases.
+type Profile struct {
+	// Name of the profile.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Rules evaluated in order when scoring a release.
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// RuleScore is the outcome of a single rule when scoring a release.
+type RuleScore struct {
+	// Name of the rule.
+	Name string `json:"name"`
+
+	// True if the release matched the rule.
+	Matched bool `json:"matched"`
+
+	// Points the rule added to the score.
+	Points int `json:"points"`
+
+	// True if the rule rejected the release.
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// Score is the result of scoring a release against a Profile.
+type Score struct {
+	// Sum of the points of every rule.
+	Total int `json:"total"`
+
+	// True if any rule rejected the release. Rejected releases keep their total so they can still be ranked.
+	Rejected bool `json:"rejected"`
+
+	// Outcome of every rule in the order they appear in the profile.
+	Rules []RuleScore `json:"rules"`
+}
+
+// LoadProfile reads a quality profile from a JSON or YAML file, chosen by the file extension.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseProfileYAML(data)
+	default:
+		return ParseProfileJSON(data)
+	}
+}
+
+// ParseProfileJSON parses and validates a quality profile written in JSON.
+func ParseProfileJSON(data []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("anitogo: invalid profile: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseProfileYAML parses and validates a quality profile written in YAML. Values are strings
+// even when they look like numbers, e.g "values: [1080]".
+func ParseProfileYAML(data []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("anitogo: invalid profile: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate returns an error if a rule has an unknown type or values that cannot be checked.
+func (p *Profile) Validate() error {
+	for i, r := range p.Rules {
+		switch r.Type {
+		case RuleTypeGroup, RuleTypeTerm, RuleTypeSource:
+			if len(r.Values) == 0 {
+				return fmt.Errorf("anitogo: rule %d (%s): no values", i, r.Type)
+			}
+		case RuleTypeMinResolution:
+			if len(r.Values) != 1 {
+				return fmt.Errorf("anitogo: rule %d (%s): expected a single resolution", i, r.Type)
+			}
+			if _, found := ParseResolution(r.Values[0]); !found {
+				return fmt.Errorf("anitogo: rule %d (%s): invalid resolution %q", i, r.Type, r.Values[0])
+			}
+		case RuleTypeChecksum:
+		default:
+			return fmt.Errorf("anitogo: rule %d: unknown type %q", i, r.Type)
+		}
+		if r.Required && r.Forbidden {
+			return fmt.Errorf("anitogo: rule %d (%s): cannot be both required and forbidden", i, r.Type)
+		}
+	}
+	return nil
+}
+
+// Score evaluates every rule of the profile against e.
+func (p *Profile) Score(e *Elements) Score {
+	s := Score{Rules: []RuleScore{}}
+	for _, r := range p.Rules {
+		rs := RuleScore{Name: r.Name, Matched: r.match(e)}
+		if rs.Name == "" {
+			rs.Name = string(r.Type)
+		}
+		if rs.Matched {
+			rs.Points = r.Weight
+		}
+		rs.Rejected = (r.Required && !rs.Matched) || (r.Forbidden && rs.Matched)
+		s.Total += rs.Points
+		s.Rejected = s.Rejected || rs.Rejected
+		s.Rules = append(s.Rules, rs)
+	}
+	return s
+}
+
+func (r Rule) match(e *Elements) bool {
+	switch r.Type {
+	case RuleTypeGroup:
+		return containsFold(r.Values, e.ReleaseGroup)
+	case RuleTypeMinResolution:
+		minimum, _ := ParseResolution(r.Values[0])
+		return e.Resolution != nil && e.Resolution.Height >= minimum.Height
+	case RuleTypeTerm:
+		for _, term := range e.terms() {
+			if containsFold(r.Values, term) {
+				return true
+			}
+		}
+	case RuleTypeSource:
+		return e.SourceType != "" && containsFold(r.Values, string(e.SourceType))
+	case RuleTypeChecksum:
+		return e.FileChecksum != ""
+	}
+	return false
+}
+
+// terms returns the raw and canonical values of every element except the titles and the file name.
+func (e *Elements) terms() []string {
+	var terms []string
+	for cat := range elementCategoryNames {
+		switch cat {
+		case elementCategoryAnimeTitle, elementCategoryEpisodeTitle, elementCategoryFileName, elementCategoryUnknown:
+			continue
+		}
+		for _, v := range e.get(cat) {
+			if v != "" {
+				terms = append(terms, v)
+			}
+		}
+	}
+	for _, values := range e.Canonical {
+		terms = append(terms, values...)
+	}
+	return terms
+}
+
+func containsFold(arr []string, content string) bool {
+	for _, v := range arr {
+		if strings.EqualFold(v, content) {
+			return true
+		}
+	}
+	return false
+}
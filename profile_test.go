@@ -0,0 +1,150 @@
+package anitogo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testProfileJSON = `{
+	"name": "default",
+	"rules": [
+		{"type": "group", "values": ["SubsPlease", "Erai-raws"], "weight": 20},
+		{"type": "min_resolution", "values": ["1080p"], "weight": 10, "required": true},
+		{"name": "hardsubs", "type": "term", "values": ["HARDSUB"], "forbidden": true},
+		{"name": "hevc", "type": "term", "values": ["H.265"], "weight": 5},
+		{"type": "checksum", "weight": 3},
+		{"type": "source", "values": ["BD"], "weight": 7}
+	]
+}`
+
+const testProfileYAML = `# Same profile as testProfileJSON.
+name: default
+rules:
+  - type: group
+    values: [SubsPlease, "Erai-raws"]
+    weight: 20
+  - type: min_resolution
+    values:
+      - 1080p
+    weight: 10
+    required: true
+  - name: hardsubs
+    type: term
+    values: ['HARDSUB']
+    forbidden: true
+  - name: hevc # matches x265 through its canonical value
+    type: term
+    values: [H.265]
+    weight: 5
+  - type: checksum
+    weight: 3
+  - type: source
+    values: [BD]
+    weight: 7
+`
+
+func TestProfileScore(t *testing.T) {
+	p, err := ParseProfileJSON([]byte(testProfileJSON))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	s := p.Score(Parse("[SubsPlease] Title - 01 (1080p) [x265] [ABCD1234].mkv", DefaultOptions))
+	if s.Total != 38 || s.Rejected {
+		t.Errorf("expected a total of 38 and not rejected, got %d and %t", s.Total, s.Rejected)
+	}
+	expected := []RuleScore{
+		{Name: "group", Matched: true, Points: 20},
+		{Name: "min_resolution", Matched: true, Points: 10},
+		{Name: "hardsubs"},
+		{Name: "hevc", Matched: true, Points: 5},
+		{Name: "checksum", Matched: true, Points: 3},
+		{Name: "source"},
+	}
+	if !reflect.DeepEqual(s.Rules, expected) {
+		t.Errorf("expected %v, got %v", expected, s.Rules)
+	}
+
+	s = p.Score(Parse("[Other] Title - 01 [BD 720p].mkv", DefaultOptions))
+	if s.Total != 7 || !s.Rejected || !s.Rules[1].Rejected {
+		t.Errorf("expected a total of 7 rejected by min_resolution, got %v", s)
+	}
+
+	s = p.Score(Parse("[Erai-raws] Title - 01 [1080p][HARDSUB].mkv", DefaultOptions))
+	if !s.Rejected || !s.Rules[2].Rejected {
+		t.Errorf("expected rejected by hardsubs, got %v", s)
+	}
+}
+
+func TestProfileParseProfileJSON(t *testing.T) {
+	invalid := []string{
+		`{"name": "a", "rules": [`,
+		`{"rules": [{"type": "unknown"}]}`,
+		`{"rules": [{"type": "min_resolution", "values": ["big"]}]}`,
+		`{"rules": [{"type": "term"}]}`,
+		`{"rules": [{"type": "min_resolution", "values": [1080]}]}`,
+	}
+	for _, data := range invalid {
+		if _, err := ParseProfileJSON([]byte(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestProfileParseProfileYAML(t *testing.T) {
+	fromJSON, err := ParseProfileJSON([]byte(testProfileJSON))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	fromYAML, err := ParseProfileYAML([]byte(testProfileYAML))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("expected %v, got %v", fromJSON, fromYAML)
+	}
+
+	p, err := ParseProfileYAML([]byte("rules:\n  - type: min_resolution\n    values: [1080]\n  - type: term\n    values: [2.0, 10]\n"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(p.Rules[0].Values, []string{"1080"}) || !reflect.DeepEqual(p.Rules[1].Values, []string{"2.0", "10"}) {
+		t.Errorf("expected numeric values as written, got %v and %v", p.Rules[0].Values, p.Rules[1].Values)
+	}
+
+	invalid := []string{
+		"name: a\n  rules: []\n",
+		"rules:\n  - type: group\n    values: [A\n",
+		"rules:\n  - type: unknown\n",
+		"rules:\n  - type: min_resolution\n    values: [big]\n",
+		"rules:\n  - type: term\n",
+	}
+	for _, data := range invalid {
+		if _, err := ParseProfileYAML([]byte(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestProfileLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{"profile.json": testProfileJSON, "profile.yml": testProfileYAML} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := LoadProfile(path)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+			continue
+		}
+		if p.Name != "default" || len(p.Rules) != 6 {
+			t.Errorf("%s: expected 6 rules, got %v", name, p)
+		}
+	}
+	if _, err := LoadProfile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
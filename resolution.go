@@ -0,0 +1,121 @@
+package anitogo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Resolution is the video resolution of the file in a form that can be compared and sorted.
+type Resolution struct {
+	// Width of the video in pixels. Zero when the filename only specifies the height, e.g "1080p".
+	Width int `json:"width,omitempty"`
+
+	// Height of the video in pixels.
+	Height int `json:"height"`
+
+	// True if the video is interlaced, e.g "1080i".
+	Interlaced bool `json:"interlaced,omitempty"`
+
+	// Canonical name of the resolution, e.g "1080p" for "1920x1080", "FHD" and "1080P".
+	Label string `json:"label"`
+}
+
+var (
+	resolutionDimensionsPattern = regexp.MustCompile("^(\\d{3,4})[xX×](\\d{3,4})$")
+	resolutionHeightPattern     = regexp.MustCompile("^(\\d{3,4})([pPiI])?$")
+	resolutionStandardHeights   = []int{240, 360, 480, 576, 720, 1080, 1440, 2160, 4320}
+	resolutionNames             = map[string]int{
+		"FHD": 1080, "QHD": 1440, "4K": 2160, "UHD": 2160, "8K": 4320,
+	}
+)
+
+// Compare returns -1 if r is a lower resolution than other, 1 if it is higher and 0 if they are equal.
+// Resolutions are ordered by height, then width, and progressive video ranks above interlaced video.
+func (r Resolution) Compare(other Resolution) int {
+	switch {
+	case r.Height != other.Height:
+		return compareInts(r.Height, other.Height)
+	case r.Width != other.Width:
+		return compareInts(r.Width, other.Width)
+	case r.Interlaced != other.Interlaced:
+		if r.Interlaced {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// ParseResolution parses a resolution as it appears in a filename, e.g "1280x720", "720p", "1080i" or "UHD".
+func ParseResolution(str string) (Resolution, bool) {
+	str = strings.TrimSpace(str)
+	if match := resolutionDimensionsPattern.FindStringSubmatch(str); match != nil {
+		width, _ := strconv.Atoi(match[1])
+		height, _ := strconv.Atoi(match[2])
+		return Resolution{
+			Width:  width,
+			Height: height,
+			Label:  fmt.Sprintf("%dp", standardHeight(width, height)),
+		}, true
+	}
+	if match := resolutionHeightPattern.FindStringSubmatch(str); match != nil {
+		height, _ := strconv.Atoi(match[1])
+		r := Resolution{
+			Height:     height,
+			Interlaced: strings.EqualFold(match[2], "i"),
+		}
+		r.Label = fmt.Sprintf("%dp", height)
+		if r.Interlaced {
+			r.Label = fmt.Sprintf("%di", height)
+		}
+		return r, true
+	}
+	if height, found := resolutionNames[strings.ToUpper(str)]; found {
+		return Resolution{
+			Height: height,
+			Label:  fmt.Sprintf("%dp", height),
+		}, true
+	}
+	return Resolution{}, false
+}
+
+// standardHeight returns the common resolution name a width and height belong to,
+// e.g 1280x692 is a cropped 720p video and 1904x1072 a 1080p one.
+func standardHeight(width, height int) int {
+	label := height
+	for _, h := range resolutionStandardHeights {
+		w := h * 16 / 9
+		if height*10 >= h*9 || width*10 >= w*9 {
+			label = h
+		}
+	}
+	return label
+}
+
+func (p *parser) buildResolution() {
+	e := p.tokenizer.elements
+	if e.VideoResolution != "" {
+		if r, found := ParseResolution(e.VideoResolution); found {
+			e.Resolution = &r
+			return
+		}
+	}
+	for _, term := range e.VideoTerm {
+		if r, found := ParseResolution(term); found {
+			e.Resolution = &r
+			return
+		}
+	}
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestResolutionParseResolution(t *testing.T) {
+	cases := map[string]Resolution{
+		"1280x720":  {Width: 1280, Height: 720, Label: "720p"},
+		"1920×1080": {Width: 1920, Height: 1080, Label: "1080p"},
+		"1904x1072": {Width: 1904, Height: 1072, Label: "1080p"},
+		"720x576":   {Width: 720, Height: 576, Label: "576p"},
+		"1080":      {Height: 1080, Label: "1080p"},
+		"720P":      {Height: 720, Label: "720p"},
+		"1080i":     {Height: 1080, Interlaced: true, Label: "1080i"},
+		"4320p":     {Height: 4320, Label: "4320p"},
+		"FHD":       {Height: 1080, Label: "1080p"},
+		"UHD":       {Height: 2160, Label: "2160p"},
+		"4K":        {Height: 2160, Label: "2160p"},
+	}
+	for str, expected := range cases {
+		r, found := ParseResolution(str)
+		if !found {
+			t.Errorf("expected \"%s\" to be a resolution", str)
+			continue
+		}
+		if r != expected {
+			t.Errorf("expected %v, got %v for \"%s\"", expected, r, str)
+		}
+	}
+	_, found := ParseResolution("HD")
+	if found {
+		t.Error("expected false, got true")
+	}
+}
+
+func TestResolutionCompare(t *testing.T) {
+	r1080p, _ := ParseResolution("1080p")
+	r1080i, _ := ParseResolution("1080i")
+	r720p, _ := ParseResolution("1280x720")
+	if r1080p.Compare(r720p) != 1 {
+		t.Error("expected 1080p to be higher than 720p")
+	}
+	if r1080i.Compare(r1080p) != -1 {
+		t.Error("expected 1080i to be lower than 1080p")
+	}
+	if r720p.Compare(r720p) != 0 {
+		t.Error("expected 720p to be equal to itself")
+	}
+}
+
+func TestResolutionBuildResolution(t *testing.T) {
+	e := Parse("[Group] Title - 01 [1920×1080 HEVC].mkv", DefaultOptions)
+	if e.Resolution == nil || e.Resolution.Label != "1080p" {
+		t.Errorf("expected \"1080p\", got %v", e.Resolution)
+	}
+	e = Parse("[Group] Title - 01 [UHD HEVC].mkv", DefaultOptions)
+	if e.Resolution == nil || e.Resolution.Height != 2160 {
+		t.Errorf("expected height 2160, got %v", e.Resolution)
+	}
+	e = Parse("[Group] Title - 01 [2160p].mkv", DefaultOptions)
+	if e.VideoResolution != "2160p" || e.Resolution == nil || e.Resolution.Label != "2160p" {
+		t.Errorf("expected \"2160p\", got %v", e.Resolution)
+	}
+	e = Parse("[Group] Title - 01.mkv", DefaultOptions)
+	if e.Resolution != nil {
+		t.Errorf("expected nil, got %v", e.Resolution)
+	}
+}
@@ -0,0 +1,116 @@
+package anitogo
+
+import (
+	"regexp"
+	"strconv"
+	"unicode"
+)
+
+// Revision holds the scene revision markers found in the filename.
+type Revision struct {
+	// Number of times the release was marked PROPER, e.g 2 for "PROPER2".
+	Proper int `json:"proper,omitempty"`
+
+	// Number of times the release was repacked, e.g 2 for "REPACK2".
+	Repack int `json:"repack,omitempty"`
+
+	// Number of times the release was re-ripped from its source, e.g 1 for "RERIP".
+	Rerip int `json:"rerip,omitempty"`
+
+	// True if the release is marked INTERNAL.
+	Internal bool `json:"internal,omitempty"`
+
+	// True if the release is marked READNFO.
+	ReadNFO bool `json:"read_nfo,omitempty"`
+
+	// True if the release is a directory name fix, e.g "DIRFIX".
+	DirFix bool `json:"dir_fix,omitempty"`
+
+	// True if the release is an NFO fix, e.g "NFOFIX".
+	NFOFix bool `json:"nfo_fix,omitempty"`
+
+	// True if the release is marked as nuked.
+	Nuked bool `json:"nuked,omitempty"`
+
+	// Number of revisions of the video itself, the sum of Proper, Repack and Rerip.
+	// A copy with a higher counter supersedes a copy of the same release with a lower one.
+	Counter int `json:"counter"`
+}
+
+var revisionPattern = regexp.MustCompile("^(PROPER|REPACK|RERIP)(\\d)?$")
+
+// searchForRevision identifies numbered revision markers, e.g "REPACK2", and the markers that are
+// common words, e.g "Proper", which are only accepted when they are not written in title case.
+func (p *parser) searchForRevision() {
+	for _, tkn := range p.tokenizer.tokens.getListFlag(tokenFlagsUnknown) {
+		normalized := p.tokenizer.keywordManager.normalize(tkn.Content)
+		kd, found := p.tokenizer.keywordManager.find(normalized, elementCategoryReleaseInformation)
+		if found && !kd.options.searchable {
+			if isTitleCase(tkn.Content) {
+				continue
+			}
+		} else if !revisionPattern.MatchString(normalized) {
+			continue
+		}
+		p.tokenizer.elements.insert(elementCategoryReleaseInformation, tkn.Content)
+		tkn.Category = tokenCategoryIdentifier
+	}
+}
+
+func (p *parser) buildRevision() {
+	e := p.tokenizer.elements
+	r := Revision{}
+	found := false
+
+	for _, info := range e.ReleaseInformation {
+		normalized := p.tokenizer.keywordManager.normalize(info)
+		if match := revisionPattern.FindStringSubmatch(normalized); match != nil {
+			count := 1
+			if match[2] != "" {
+				count, _ = strconv.Atoi(match[2])
+			}
+			switch match[1] {
+			case "PROPER":
+				r.Proper = max(r.Proper, count)
+			case "REPACK":
+				r.Repack = max(r.Repack, count)
+			case "RERIP":
+				r.Rerip = max(r.Rerip, count)
+			}
+			found = true
+			continue
+		}
+		switch normalized {
+		case "INTERNAL":
+			r.Internal = true
+		case "READNFO":
+			r.ReadNFO = true
+		case "DIRFIX":
+			r.DirFix = true
+		case "NFOFIX":
+			r.NFOFix = true
+		case "NUKED":
+			r.Nuked = true
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if found {
+		r.Counter = r.Proper + r.Repack + r.Rerip
+		e.Revision = &r
+	}
+}
+
+func isTitleCase(str string) bool {
+	for i, r := range str {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if i > 0 && !unicode.IsLower(r) {
+			return false
+		}
+	}
+	return len(str) > 1
+}
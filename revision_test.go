@@ -0,0 +1,50 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestRevisionBuildRevision(t *testing.T) {
+	e := Parse("Title.S01E02.PROPER.REPACK2.1080p.WEB-DL.H.264.mkv", DefaultOptions)
+	if e.Revision == nil {
+		t.Fatal("expected revision, got nil")
+	}
+	if e.Revision.Proper != 1 || e.Revision.Repack != 2 || e.Revision.Counter != 3 {
+		t.Errorf("expected PROPER and REPACK2 with a counter of 3, got %v", e.Revision)
+	}
+
+	e = Parse("Title.S01E02.iNTERNAL.READNFO.RERIP.DIRFIX.NFOFIX.1080p.BluRay.x264.mkv", DefaultOptions)
+	if e.Revision == nil {
+		t.Fatal("expected revision, got nil")
+	}
+	if !e.Revision.Internal || !e.Revision.ReadNFO || !e.Revision.DirFix || !e.Revision.NFOFix {
+		t.Errorf("expected INTERNAL, READNFO, DIRFIX and NFOFIX, got %v", e.Revision)
+	}
+	if e.Revision.Rerip != 1 || e.Revision.Counter != 1 {
+		t.Errorf("expected RERIP with a counter of 1, got %v", e.Revision)
+	}
+
+	e = Parse("[Group] Proper Title - 01 [1080p].mkv", DefaultOptions)
+	if e.Revision != nil {
+		t.Errorf("expected nil, got %v", e.Revision)
+	}
+	if e.AnimeTitle != "Proper Title" {
+		t.Errorf("expected \"Proper Title\", got \"%s\"", e.AnimeTitle)
+	}
+
+	e = Parse("[Group] Title - 01 [1080p][NUKED].mkv", DefaultOptions)
+	if e.Revision == nil || !e.Revision.Nuked || e.Revision.Counter != 0 {
+		t.Errorf("expected NUKED with a counter of 0, got %v", e.Revision)
+	}
+}
+
+func TestRevisionIsTitleCase(t *testing.T) {
+	if !isTitleCase("Proper") {
+		t.Error("expected true, got false")
+	}
+	for _, s := range []string{"PROPER", "iNTERNAL", "proper", "P"} {
+		if isTitleCase(s) {
+			t.Errorf("expected \"%s\" not to be title case", s)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package server exposes the anitogo parser over HTTP with a JSON API.
+//
+// The endpoints are:
+//
+//	POST /parse        {"name": "..."} or {"names": ["...", ...]}, with optional "options"
+//	POST /parse/path   {"path": "..."} or {"paths": ["...", ...]}, with optional "options"
+//	GET  /keywords     keywords recognised by the parser, grouped by element
+//	GET  /health       {"status": "ok"}
+//
+// A single name returns {"elements": {...}} and a batch returns {"results": [{...}, ...]} in the same order.
+// Errors return {"error": "..."} with a 4xx status.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// DefaultOptions is a variable configured with the recommended defaults for the Options struct to be passed to NewHandler.
+var DefaultOptions = Options{
+	MaxBodyBytes: 1 << 20,
+	MaxBatch:     1000,
+}
+
+// Options configures the handler.
+type Options struct {
+	// DefaultOptions value: 1 << 20
+	// Requests with a larger body are rejected with 413 Request Entity Too Large.
+	MaxBodyBytes int64
+
+	// DefaultOptions value: 1000
+	// Batches with more names are rejected with 413 Request Entity Too Large.
+	MaxBatch int
+}
+
+// ParseOptions are the parser options of a request. Options left out keep the value of anitogo.DefaultOptions.
+type ParseOptions struct {
+	AllowedDelimiters  *string  `json:"allowed_delimiters,omitempty"`
+	IgnoredStrings     []string `json:"ignored_strings,omitempty"`
+	ParseEpisodeNumber *bool    `json:"parse_episode_number,omitempty"`
+	ParseEpisodeTitle  *bool    `json:"parse_episode_title,omitempty"`
+	ParseFileExtension *bool    `json:"parse_file_extension,omitempty"`
+	ParseReleaseGroup  *bool    `json:"parse_release_group,omitempty"`
+}
+
+// Options returns the anitogo options of the request.
+func (o *ParseOptions) Options() anitogo.Options {
+	options := anitogo.DefaultOptions
+	if o == nil {
+		return options
+	}
+	if o.AllowedDelimiters != nil {
+		options.AllowedDelimiters = *o.AllowedDelimiters
+	}
+	if o.IgnoredStrings != nil {
+		options.IgnoredStrings = o.IgnoredStrings
+	}
+	if o.ParseEpisodeNumber != nil {
+		options.ParseEpisodeNumber = *o.ParseEpisodeNumber
+	}
+	if o.ParseEpisodeTitle != nil {
+		options.ParseEpisodeTitle = *o.ParseEpisodeTitle
+	}
+	if o.ParseFileExtension != nil {
+		options.ParseFileExtension = *o.ParseFileExtension
+	}
+	if o.ParseReleaseGroup != nil {
+		options.ParseReleaseGroup = *o.ParseReleaseGroup
+	}
+	return options
+}
+
+// parseRequest is the body of the parse endpoints. /parse uses Name and Names, /parse/path uses Path and Paths.
+type parseRequest struct {
+	Name    string        `json:"name,omitempty"`
+	Names   []string      `json:"names,omitempty"`
+	Path    string        `json:"path,omitempty"`
+	Paths   []string      `json:"paths,omitempty"`
+	Options *ParseOptions `json:"options,omitempty"`
+}
+
+type parseResponse struct {
+	Elements *anitogo.Elements `json:"elements"`
+}
+
+type batchResponse struct {
+	Results []*anitogo.Elements `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type handler struct {
+	options Options
+	mux     *http.ServeMux
+}
+
+// NewHandler returns the handler serving the API.
+func NewHandler(options Options) http.Handler {
+	h := &handler{options: options, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/parse", h.method(http.MethodPost, h.parse(false)))
+	h.mux.HandleFunc("/parse/path", h.method(http.MethodPost, h.parse(true)))
+	h.mux.HandleFunc("/keywords", h.method(http.MethodGet, h.keywords))
+	h.mux.HandleFunc("/health", h.method(http.MethodGet, h.health))
+	h.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no endpoint at "+r.URL.Path)
+	})
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// method rejects requests that do not use the method with 405 Method Not Allowed.
+func (h *handler) method(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method && !(method == http.MethodGet && r.Method == http.MethodHead) {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s requires %s", r.URL.Path, method))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *handler) parse(paths bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req parseRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.options.MaxBodyBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is larger than %d bytes", maxBytesErr.Limit))
+				return
+			}
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		single, batch, field := req.Name, req.Names, "name"
+		other := req.Path != "" || req.Paths != nil
+		parse := anitogo.Parse
+		if paths {
+			single, batch, field = req.Path, req.Paths, "path"
+			other = req.Name != "" || req.Names != nil
+			parse = anitogo.ParsePath
+		}
+		switch {
+		case other:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s only accepts %q or %q", r.URL.Path, field, field+"s"))
+			return
+		case (single == "") == (batch == nil):
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("expected either %q or %q", field, field+"s"))
+			return
+		case len(batch) > h.options.MaxBatch:
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("batch of %d is larger than %d", len(batch), h.options.MaxBatch))
+			return
+		}
+
+		options := req.Options.Options()
+		if batch == nil {
+			writeJSON(w, http.StatusOK, parseResponse{Elements: parse(single, options)})
+			return
+		}
+		results := make([]*anitogo.Elements, len(batch))
+		for i, name := range batch {
+			results[i] = parse(name, options)
+		}
+		writeJSON(w, http.StatusOK, batchResponse{Results: results})
+	}
+}
+
+func (h *handler) keywords(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, anitogo.Keywords())
+}
+
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// Run serves on srv until ctx is done, then stops accepting connections and waits up to
+// shutdownTimeout for the requests in progress to finish.
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
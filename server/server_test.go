@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func do(t *testing.T, h http.Handler, method, target, body string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a JSON object from %s %s, got %q", method, target, rec.Body.String())
+	}
+	return rec, resp
+}
+
+func TestServerParse(t *testing.T) {
+	h := NewHandler(DefaultOptions)
+
+	rec, resp := do(t, h, http.MethodPost, "/parse", `{"name": "[Group] Title - 05 [1080p].mkv"}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected 200 with JSON, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var e anitogo.Elements
+	json.Unmarshal(resp["elements"], &e)
+	if e.AnimeTitle != "Title" || e.ReleaseGroup != "Group" {
+		t.Errorf("expected Title by Group, got %+v", e)
+	}
+
+	body := `{"names": ["[Group] Title - 05.mkv", "[Other] Show - 12.mkv"], "options": {"parse_release_group": false}}`
+	_, resp = do(t, h, http.MethodPost, "/parse", body)
+	var results []anitogo.Elements
+	json.Unmarshal(resp["results"], &results)
+	if len(results) != 2 || results[1].AnimeTitle != "Show" || results[0].ReleaseGroup != "" {
+		t.Errorf("expected 2 results without release groups, got %+v", results)
+	}
+}
+
+func TestServerParsePath(t *testing.T) {
+	h := NewHandler(DefaultOptions)
+	_, resp := do(t, h, http.MethodPost, "/parse/path", `{"path": "[Group] Title (BD 1080p)/Season 2/05.mkv"}`)
+	var e anitogo.Elements
+	json.Unmarshal(resp["elements"], &e)
+	if e.AnimeTitle != "Title" || len(e.AnimeSeason) != 1 || e.AnimeSeason[0] != "2" {
+		t.Errorf("expected Title season 2 from the directories, got %+v", e)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	options := DefaultOptions
+	options.MaxBodyBytes = 128
+	options.MaxBatch = 2
+	h := NewHandler(options)
+	tests := []struct {
+		method, target, body string
+		status               int
+	}{
+		{http.MethodGet, "/parse", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/parse", `{"name": `, http.StatusBadRequest},
+		{http.MethodPost, "/parse", `{"title": "x"}`, http.StatusBadRequest},
+		{http.MethodPost, "/parse", `{}`, http.StatusBadRequest},
+		{http.MethodPost, "/parse", `{"name": "a", "names": ["b"]}`, http.StatusBadRequest},
+		{http.MethodPost, "/parse", `{"path": "a"}`, http.StatusBadRequest},
+		{http.MethodPost, "/parse", `{"names": ["a", "b", "c"]}`, http.StatusRequestEntityTooLarge},
+		{http.MethodPost, "/parse", `{"name": "` + strings.Repeat("a", 200) + `"}`, http.StatusRequestEntityTooLarge},
+		{http.MethodGet, "/nope", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		rec, resp := do(t, h, test.method, test.target, test.body)
+		if rec.Code != test.status || resp["error"] == nil {
+			t.Errorf("expected %d with an error for %s %s %s, got %d %s", test.status, test.method, test.target, test.body, rec.Code, rec.Body)
+		}
+	}
+	if rec, _ := do(t, h, http.MethodPut, "/health", ""); rec.Header().Get("Allow") != http.MethodGet {
+		t.Errorf("expected an Allow header, got %q", rec.Header().Get("Allow"))
+	}
+}
+
+func TestServerKeywords(t *testing.T) {
+	h := NewHandler(DefaultOptions)
+	rec, resp := do(t, h, http.MethodGet, "/keywords", "")
+	var extensions []string
+	json.Unmarshal(resp["file_extension"], &extensions)
+	if rec.Code != http.StatusOK || len(extensions) == 0 {
+		t.Errorf("expected file extensions, got %d %s", rec.Code, rec.Body)
+	}
+	if rec, resp := do(t, h, http.MethodGet, "/health", ""); rec.Code != http.StatusOK || string(resp["status"]) != `"ok"` {
+		t.Errorf("expected status ok, got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestServerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: NewHandler(DefaultOptions)}
+	done := make(chan error, 1)
+	go func() { done <- Run(ctx, srv, time.Second) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected a graceful shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return after the context was cancelled")
+	}
+}
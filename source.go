@@ -0,0 +1,130 @@
+package anitogo
+
+import "strings"
+
+// SourceType is the kind of media a video was ripped from.
+type SourceType string
+
+const (
+	// SourceTypeBD is used for Blu-ray discs, e.g "BD", "BDRip" or "Blu-Ray".
+	SourceTypeBD SourceType = "BD"
+
+	// SourceTypeDVD is used for DVDs, e.g "DVD", "DVDRip" or "R2J".
+	SourceTypeDVD SourceType = "DVD"
+
+	// SourceTypeTV is used for TV broadcasts, e.g "HDTV" or "TVRip".
+	SourceTypeTV SourceType = "TV"
+
+	// SourceTypeWEBDL is used for files downloaded from a streaming service, e.g "WEB-DL" or "WEB".
+	SourceTypeWEBDL SourceType = "WEB-DL"
+
+	// SourceTypeWEBRip is used for streams captured from a streaming service, e.g "WEBRip" or "WEBCast".
+	SourceTypeWEBRip SourceType = "WEBRip"
+
+	// SourceTypeLaserDisc is used for LaserDiscs, e.g "LaserDisc" or "LDRip".
+	SourceTypeLaserDisc SourceType = "LaserDisc"
+
+	// SourceTypeVHS is used for VHS tapes, e.g "VHS" or "VHSRip".
+	SourceTypeVHS SourceType = "VHS"
+)
+
+var sourceTypeTable = map[string]SourceType{
+	"BD": SourceTypeBD, "BDRIP": SourceTypeBD, "BLURAY": SourceTypeBD, "BLU-RAY": SourceTypeBD,
+	"DVD": SourceTypeDVD, "DVD5": SourceTypeDVD, "DVD9": SourceTypeDVD, "DVD-R2J": SourceTypeDVD,
+	"DVDRIP": SourceTypeDVD, "DVD-RIP": SourceTypeDVD, "R2DVD": SourceTypeDVD, "R2J": SourceTypeDVD,
+	"R2JDVD": SourceTypeDVD, "R2JDVDRIP": SourceTypeDVD,
+	"HDTV": SourceTypeTV, "HDTVRIP": SourceTypeTV, "TVRIP": SourceTypeTV, "TV-RIP": SourceTypeTV,
+	// Untagged web releases are downloads in the vast majority of cases.
+	"WEB": SourceTypeWEBDL, "WEB-DL": SourceTypeWEBDL, "WEBDL": SourceTypeWEBDL,
+	"WEBRIP": SourceTypeWEBRip, "WEB-RIP": SourceTypeWEBRip, "WEBCAST": SourceTypeWEBRip,
+	"LASERDISC": SourceTypeLaserDisc, "LDRIP": SourceTypeLaserDisc,
+	"VHS": SourceTypeVHS, "VHSRIP": SourceTypeVHS,
+}
+
+// searchForStreamingService identifies the short codes of streaming services, e.g "CR" or "AMZN".
+// Short codes are only accepted next to a web source or inside brackets holding other identifiers,
+// since they could otherwise be a part of the title.
+func (p *parser) searchForStreamingService() {
+	if p.tokenizer.elements.contains(elementCategoryStreamingService) {
+		return
+	}
+	for _, tkn := range p.tokenizer.tokens.getListFlag(tokenFlagsUnknown) {
+		kd, found := p.tokenizer.keywordManager.find(p.tokenizer.keywordManager.normalize(tkn.Content), elementCategoryStreamingService)
+		if !found || kd.options.searchable {
+			continue
+		}
+		if !p.isNextToWebSource(tkn) && !p.isEnclosedWithIdentifiers(tkn) {
+			continue
+		}
+		p.tokenizer.elements.insert(elementCategoryStreamingService, tkn.Content)
+		tkn.Category = tokenCategoryIdentifier
+		return
+	}
+}
+
+// searchForSource identifies the ambiguous sources, e.g "WEB" or "VHS". They are only accepted
+// next to another identifier or inside brackets holding other identifiers, since they could
+// otherwise be a part of the title.
+func (p *parser) searchForSource() {
+	for _, tkn := range p.tokenizer.tokens.getListFlag(tokenFlagsUnknown) {
+		kd, found := p.tokenizer.keywordManager.find(p.tokenizer.keywordManager.normalize(tkn.Content), elementCategorySource)
+		if !found || kd.options.searchable {
+			continue
+		}
+		if !p.isNextToIdentifier(tkn) && !p.isEnclosedWithIdentifiers(tkn) {
+			continue
+		}
+		p.tokenizer.elements.insert(elementCategorySource, tkn.Content)
+		tkn.Category = tokenCategoryIdentifier
+	}
+}
+
+func (p *parser) isNextToIdentifier(tkn *token) bool {
+	previous, _ := p.tokenizer.tokens.findPrevious(*tkn, tokenFlagsNotDelimiter)
+	next, _ := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+	return previous.Category == tokenCategoryIdentifier || next.Category == tokenCategoryIdentifier
+}
+
+func (p *parser) isNextToWebSource(tkn *token) bool {
+	previous, _ := p.tokenizer.tokens.findPrevious(*tkn, tokenFlagsNotDelimiter)
+	next, _ := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+	for _, t := range []*token{previous, next} {
+		if t.empty() || t.Category == tokenCategoryBracket {
+			continue
+		}
+		normalized := p.tokenizer.keywordManager.normalize(t.Content)
+		if strings.HasPrefix(normalized, "WEB") && sourceTypeTable[normalized] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) isEnclosedWithIdentifiers(tkn *token) bool {
+	if !tkn.Enclosed {
+		return false
+	}
+	tkns := *p.tokenizer.tokens
+	index := p.tokenizer.tokens.getIndex(*tkn, 0)
+	for _, step := range []int{-1, 1} {
+		for i := index + step; i >= 0 && i < len(tkns); i += step {
+			if tkns[i].Category == tokenCategoryBracket {
+				break
+			}
+			if tkns[i].Category == tokenCategoryIdentifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *parser) buildSourceType() {
+	e := p.tokenizer.elements
+	for _, source := range e.Source {
+		if st, found := sourceTypeTable[p.tokenizer.keywordManager.normalize(source)]; found {
+			e.SourceType = st
+			return
+		}
+	}
+}
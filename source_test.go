@@ -0,0 +1,75 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestSourceSearchForStreamingService(t *testing.T) {
+	e := Parse("[Erai-raws] Title - 01 [1080p CR WEB-DL AVC AAC].mkv", DefaultOptions)
+	if e.StreamingService != "CR" {
+		t.Errorf("expected \"CR\", got \"%s\"", e.StreamingService)
+	}
+	if !equal(e.Canonical["streaming_service"], []string{"Crunchyroll"}) {
+		t.Errorf("expected [Crunchyroll], got %v", e.Canonical["streaming_service"])
+	}
+
+	e = Parse("Title.S01E02.1080p.AMZN.WEB-DL.DDP2.0.H.264.mkv", DefaultOptions)
+	if e.StreamingService != "AMZN" {
+		t.Errorf("expected \"AMZN\", got \"%s\"", e.StreamingService)
+	}
+
+	e = Parse("[SubsPlease] Title - 01 (1080p) [HIDIVE].mkv", DefaultOptions)
+	if e.StreamingService != "HIDIVE" {
+		t.Errorf("expected \"HIDIVE\", got \"%s\"", e.StreamingService)
+	}
+
+	e = Parse("[Group] NF Title - 01 [1080p].mkv", DefaultOptions)
+	if e.StreamingService != "" {
+		t.Errorf("expected empty streaming service, got \"%s\"", e.StreamingService)
+	}
+	if e.AnimeTitle != "NF Title" {
+		t.Errorf("expected \"NF Title\", got \"%s\"", e.AnimeTitle)
+	}
+
+	e = Parse("[NF] Title - 01 [1080p].mkv", DefaultOptions)
+	if e.StreamingService != "" || e.ReleaseGroup != "NF" {
+		t.Errorf("expected release group \"NF\", got streaming service \"%s\"", e.StreamingService)
+	}
+}
+
+func TestSourceBuildSourceType(t *testing.T) {
+	cases := map[string]SourceType{
+		"[Group] Title - 01 [BDRip 1080p].mkv":     SourceTypeBD,
+		"[Group] Title - 01 [Blu-Ray 1080p].mkv":   SourceTypeBD,
+		"[Group] Title - 01 [DVD-RIP 480p].mkv":    SourceTypeDVD,
+		"[Group] Title - 01 [HDTV 720p].mkv":       SourceTypeTV,
+		"[Group] Title - 01 [WEB 1080p].mkv":       SourceTypeWEBDL,
+		"[Group] Title - 01 [CR WEB-DL 1080p].mkv": SourceTypeWEBDL,
+		"[Group] Title - 01 [B-Global WEBRip].mkv": SourceTypeWEBRip,
+		"[Group] Title - 01 [LaserDisc 480p].mkv":  SourceTypeLaserDisc,
+		"[Group] Title - 01 [VHSRip].mkv":          SourceTypeVHS,
+		"[Group] Title - 01 [1080p].mkv":           "",
+		"[Group] Title - 01 [CR WEB].mkv":          SourceTypeWEBDL,
+		"Title.S01E02.1080p.WEB.H.264.mkv":         SourceTypeWEBDL,
+		"[Group] Title - 01 [VHS 480p].mkv":        SourceTypeVHS,
+		"[Group] Web Ghosts - 01 [1080p].mkv":      "",
+	}
+	for filename, expected := range cases {
+		e := Parse(filename, DefaultOptions)
+		if e.SourceType != expected {
+			t.Errorf("expected \"%s\", got \"%s\" for \"%s\"", expected, e.SourceType, filename)
+		}
+	}
+}
+
+func TestSourceSearchForSource(t *testing.T) {
+	e := Parse("[Group] Web Ghosts - 01 [1080p].mkv", DefaultOptions)
+	if e.AnimeTitle != "Web Ghosts" || len(e.Source) != 0 {
+		t.Errorf("expected \"Web Ghosts\" without a source, got \"%s\" and %v", e.AnimeTitle, e.Source)
+	}
+
+	e = Parse("[Group] VHS Days - 01 [VHS 480p].mkv", DefaultOptions)
+	if e.AnimeTitle != "VHS Days" || !equal(e.Source, []string{"VHS"}) {
+		t.Errorf("expected \"VHS Days\" from [VHS], got \"%s\" and %v", e.AnimeTitle, e.Source)
+	}
+}
@@ -0,0 +1,129 @@
+package torrent
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// decoder decodes bencoded data into int64, string, []interface{} and map[string]interface{} values.
+type decoder struct {
+	data []byte
+	pos  int
+
+	// Start and end offsets of the value of the "info" key of the top level dictionary.
+	infoStart, infoEnd int
+}
+
+// decodeBencode decodes a single bencoded value that must span all of data.
+func decodeBencode(data []byte) (interface{}, *decoder, error) {
+	d := &decoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, d, err
+	}
+	if d.pos != len(d.data) {
+		return nil, d, d.errorf("trailing data after the top level value")
+	}
+	return v, d, nil
+}
+
+// maxDepth limits the nesting of lists and dictionaries so malformed files cannot exhaust the stack.
+const maxDepth = 64
+
+func (d *decoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("torrent: invalid bencode at offset %d: %s", d.pos, fmt.Sprintf(format, args...))
+}
+
+func (d *decoder) decode(depth int) (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, d.errorf("unexpected end of data")
+	}
+	if depth > maxDepth {
+		return nil, d.errorf("nested too deeply")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.decodeInt()
+	case c == 'l':
+		d.pos++
+		list := []interface{}{}
+		for {
+			if d.pos >= len(d.data) {
+				return nil, d.errorf("unterminated list")
+			}
+			if d.data[d.pos] == 'e' {
+				d.pos++
+				return list, nil
+			}
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == 'd':
+		d.pos++
+		dict := map[string]interface{}{}
+		for {
+			if d.pos >= len(d.data) {
+				return nil, d.errorf("unterminated dictionary")
+			}
+			if d.data[d.pos] == 'e' {
+				d.pos++
+				return dict, nil
+			}
+			key, err := d.decodeString()
+			if err != nil {
+				return nil, err
+			}
+			start := d.pos
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 && key == "info" {
+				d.infoStart, d.infoEnd = start, d.pos
+			}
+			dict[key] = v
+		}
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, d.errorf("unexpected character %q", c)
+	}
+}
+
+func (d *decoder) decodeInt() (int64, error) {
+	end := d.pos + 1
+	for end < len(d.data) && d.data[end] != 'e' {
+		end++
+	}
+	if end >= len(d.data) {
+		return 0, d.errorf("unterminated integer")
+	}
+	n, err := strconv.ParseInt(string(d.data[d.pos+1:end]), 10, 64)
+	if err != nil {
+		return 0, d.errorf("invalid integer %q", d.data[d.pos+1:end])
+	}
+	d.pos = end + 1
+	return n, nil
+}
+
+func (d *decoder) decodeString() (string, error) {
+	colon := d.pos
+	for colon < len(d.data) && d.data[colon] != ':' {
+		colon++
+	}
+	if colon >= len(d.data) {
+		return "", d.errorf("unterminated string length")
+	}
+	length, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil || length < 0 {
+		return "", d.errorf("invalid string length %q", d.data[d.pos:colon])
+	}
+	if length > len(d.data)-colon-1 {
+		return "", d.errorf("string of %d bytes exceeds the data", length)
+	}
+	d.pos = colon + 1 + length
+	return string(d.data[colon+1 : d.pos]), nil
+}
@@ -0,0 +1,140 @@
+package torrent
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// Magnet is the content of a magnet link.
+type Magnet struct {
+	// SHA-1 info hash from the "urn:btih:" exact topic in lowercase hex. Base32 hashes are converted to hex.
+	InfoHash string `json:"info_hash,omitempty"`
+
+	// SHA-256 info hash of v2 torrents from the "urn:btmh:" exact topic in lowercase hex, without the multihash prefix.
+	InfoHashV2 string `json:"info_hash_v2,omitempty"`
+
+	// Decoded display name ("dn").
+	Name string `json:"name,omitempty"`
+
+	// Exact length in bytes ("xl"). Zero if the link does not say.
+	Length int64 `json:"length,omitempty"`
+
+	// Tracker URLs ("tr") in the order they appear.
+	Trackers []string `json:"trackers,omitempty"`
+
+	// Elements parsed from the display name. Nil if the link has no display name.
+	Elements *anitogo.Elements `json:"elements,omitempty"`
+}
+
+// ParseMagnet parses a magnet link and its display name with the specified options.
+//
+// Display names are decoded the way browsers encode them: "%XX" is the byte XX and "+" is a space,
+// so "Title+-+01+%5B1080p%5D" is parsed as "Title - 01 [1080p]" and an encoded plus, "%2B", stays a plus.
+// An error is returned if the link has no info hash or an invalid one.
+func ParseMagnet(uri string, options anitogo.Options) (*Magnet, error) {
+	const prefix = "magnet:?"
+	if len(uri) < len(prefix) || !strings.EqualFold(uri[:len(prefix)], prefix) {
+		return nil, errors.New("torrent: not a magnet link")
+	}
+
+	m := &Magnet{}
+	for _, param := range strings.Split(uri[len(prefix):], "&") {
+		key, value, _ := strings.Cut(param, "=")
+		value = decodeMagnetValue(value)
+		// Parameters may be numbered when repeated, e.g "tr.1" and "tr.2".
+		key, _, _ = strings.Cut(strings.ToLower(key), ".")
+
+		switch key {
+		case "xt":
+			if err := m.setExactTopic(value); err != nil {
+				return nil, err
+			}
+		case "dn":
+			if m.Name == "" {
+				m.Name = strings.TrimSpace(value)
+			}
+		case "xl":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("torrent: invalid exact length %q", value)
+			}
+			m.Length = n
+		case "tr":
+			if value != "" {
+				m.Trackers = append(m.Trackers, value)
+			}
+		}
+	}
+	if m.InfoHash == "" && m.InfoHashV2 == "" {
+		return nil, errors.New("torrent: magnet link has no info hash")
+	}
+	if m.Name != "" {
+		m.Elements = anitogo.Parse(m.Name, options)
+	}
+	return m, nil
+}
+
+func (m *Magnet) setExactTopic(topic string) error {
+	lower := strings.ToLower(topic)
+	switch {
+	case strings.HasPrefix(lower, "urn:btih:"):
+		hash := topic[len("urn:btih:"):]
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err != nil {
+				return fmt.Errorf("torrent: invalid info hash %q", hash)
+			}
+			m.InfoHash = strings.ToLower(hash)
+		case 32:
+			b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+			if err != nil {
+				return fmt.Errorf("torrent: invalid info hash %q", hash)
+			}
+			m.InfoHash = hex.EncodeToString(b)
+		default:
+			return fmt.Errorf("torrent: invalid info hash %q", hash)
+		}
+	case strings.HasPrefix(lower, "urn:btmh:"):
+		hash := lower[len("urn:btmh:"):]
+		// Multihash prefix of SHA-256: function 0x12 and length 0x20.
+		if len(hash) != 68 || !strings.HasPrefix(hash, "1220") {
+			return fmt.Errorf("torrent: invalid v2 info hash %q", hash)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return fmt.Errorf("torrent: invalid v2 info hash %q", hash)
+		}
+		m.InfoHashV2 = hash[4:]
+	}
+	return nil
+}
+
+// decodeMagnetValue decodes a query value. Invalid escapes are kept as they are instead of failing the whole link.
+func decodeMagnetValue(value string) string {
+	if decoded, err := url.QueryUnescape(value); err == nil {
+		return decoded
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '+':
+			b.WriteByte(' ')
+		case c == '%' && i+2 < len(value):
+			if n, err := strconv.ParseUint(value[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 2
+				continue
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package torrent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+func TestMagnetParseMagnet(t *testing.T) {
+	m, err := ParseMagnet("magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567"+
+		"&dn=%5BSubsPlease%5D+Sousou+no+Frieren+-+05+%281080p%29+%5BA1B2C3D4%5D.mkv"+
+		"&xl=1503238553&tr=http%3A%2F%2Fnyaa.tracker.wf%3A7777%2Fannounce&tr=udp%3A%2F%2Fopen.stealth.si%3A80%2Fannounce",
+		anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if m.InfoHash != "0123456789abcdef0123456789abcdef01234567" || m.Length != 1503238553 {
+		t.Errorf("expected the info hash and length, got %s and %d", m.InfoHash, m.Length)
+	}
+	if m.Name != "[SubsPlease] Sousou no Frieren - 05 (1080p) [A1B2C3D4].mkv" {
+		t.Errorf("expected the decoded name, got %s", m.Name)
+	}
+	expectedTrackers := []string{"http://nyaa.tracker.wf:7777/announce", "udp://open.stealth.si:80/announce"}
+	if !reflect.DeepEqual(m.Trackers, expectedTrackers) {
+		t.Errorf("expected %v, got %v", expectedTrackers, m.Trackers)
+	}
+	if m.Elements.AnimeTitle != "Sousou no Frieren" || m.Elements.ReleaseGroup != "SubsPlease" || m.Elements.FileChecksum != "A1B2C3D4" {
+		t.Errorf("expected the parsed name, got %v", m.Elements)
+	}
+
+	// An encoded plus belongs to the name, a plain plus is a space.
+	m, err = ParseMagnet("magnet:?xt=urn:btih:AEAQCAIBAEAQCAIBAEAQCAIBAEAQCAIB&dn=Title+-+01+%5BAAC%2BFLAC%5D+%5B50%25%5D", anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if m.InfoHash != "0101010101010101010101010101010101010101" {
+		t.Errorf("expected the base32 info hash in hex, got %s", m.InfoHash)
+	}
+	if m.Name != "Title - 01 [AAC+FLAC] [50%]" || m.Elements.AnimeTitle != "Title" {
+		t.Errorf("expected \"Title - 01 [AAC+FLAC] [50%%]\", got %s and \"%s\"", m.Name, m.Elements.AnimeTitle)
+	}
+
+	// Invalid escapes are kept instead of failing the link.
+	m, err = ParseMagnet("magnet:?dn=Title+100%+-+01&xt=urn:btmh:1220"+
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if m.Name != "Title 100% - 01" || m.InfoHashV2 != "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("expected the name and v2 info hash, got %s and %s", m.Name, m.InfoHashV2)
+	}
+
+	invalid := []string{
+		"http://example.com",
+		"magnet:?dn=Title",
+		"magnet:?xt=urn:btih:0123",
+		"magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef0123456Z",
+		"magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&xl=big",
+	}
+	for _, uri := range invalid {
+		if _, err := ParseMagnet(uri, anitogo.DefaultOptions); err == nil {
+			t.Errorf("expected an error for %s", uri)
+		}
+	}
+}
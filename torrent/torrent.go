@@ -0,0 +1,313 @@
+// Package torrent reads .torrent metainfo files and parses the name of every file they contain with anitogo.
+package torrent
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// FileKind is the role of a file in a torrent.
+type FileKind string
+
+const (
+	// FileKindEpisode is a video with an episode number.
+	FileKindEpisode FileKind = "episode"
+
+	// FileKindExtra is a video that is not part of the episodes, e.g a creditless opening or a menu.
+	FileKindExtra FileKind = "extra"
+
+	// FileKindVideo is a video without an episode number that is not an extra, e.g a movie.
+	FileKindVideo FileKind = "video"
+
+	// FileKindSubtitle is an external subtitle file, e.g ".ass" or ".srt".
+	FileKindSubtitle FileKind = "subtitle"
+
+	// FileKindFont is a font used by the subtitles.
+	FileKindFont FileKind = "font"
+
+	// FileKindAudio is an external audio track, e.g ".mka" or ".flac".
+	FileKindAudio FileKind = "audio"
+
+	// FileKindOther is any other file, e.g an NFO or scans.
+	FileKindOther FileKind = "other"
+)
+
+var (
+	subtitleExtensions = []string{"ass", "idx", "srt", "ssa", "sub", "sup", "vtt"}
+	fontExtensions     = []string{"otf", "ttc", "ttf", "woff", "woff2"}
+	audioExtensions    = []string{"aac", "ac3", "dts", "flac", "m4a", "mka", "mp3", "ogg", "opus", "wav"}
+	extraDirectories   = []string{"bonus", "cm", "extra", "extras", "menu", "menus", "nc", "nced", "ncop", "preview", "previews", "pv", "trailers"}
+	extraAnimeTypes    = []string{"ED", "ENDING", "NCED", "NCOP", "OP", "OPENING", "PREVIEW", "PV"}
+)
+
+// File is a file of a torrent.
+type File struct {
+	// Path of the file inside the torrent, including the directory named after the torrent in multi-file torrents.
+	Path string `json:"path"`
+
+	// Size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// Role of the file in the torrent.
+	Kind FileKind `json:"kind"`
+
+	// Elements parsed from the path of videos, subtitles and audio tracks. Nil for other kinds.
+	Elements *anitogo.Elements `json:"elements,omitempty"`
+}
+
+// EpisodeRange is the range of episode numbers covered by the episodes of a torrent.
+type EpisodeRange struct {
+	// Lowest episode number.
+	First float64 `json:"first"`
+
+	// Highest episode number.
+	Last float64 `json:"last"`
+
+	// Number of distinct episode numbers, which is lower than the size of the range if episodes are missing.
+	Count int `json:"count"`
+}
+
+// Torrent is the content of a .torrent file.
+type Torrent struct {
+	// Name of the torrent, which is the name of the file in single-file torrents
+	// and the name of the top level directory in multi-file torrents.
+	Name string `json:"name"`
+
+	// SHA-1 hash of the info dictionary in lowercase hex, i.e the v1 info hash.
+	InfoHash string `json:"info_hash"`
+
+	// Sum of the sizes of the files in bytes.
+	Size int64 `json:"size"`
+
+	// Elements parsed from the name of the torrent.
+	Elements *anitogo.Elements `json:"elements"`
+
+	// Files of the torrent in the order they are listed, without padding files.
+	Files []File `json:"files"`
+
+	// Range of the episodes in the torrent. Nil if it has none.
+	Episodes *EpisodeRange `json:"episodes,omitempty"`
+}
+
+// ReadFile reads the .torrent file at name and parses the name of every file it contains with the specified options.
+func ReadFile(name string, options anitogo.Options) (*Torrent, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, options)
+}
+
+// Read reads a .torrent file from r and parses the name of every file it contains with the specified options.
+// Both v1 file lists and v2 file trees are supported.
+func Read(r io.Reader, options anitogo.Options) (*Torrent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	v, d, err := decodeBencode(data)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("torrent: top level value is not a dictionary")
+	}
+	info, ok := root["info"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("torrent: missing info dictionary")
+	}
+
+	t := &Torrent{Name: stringValue(info, "name"), Files: []File{}}
+	if t.Name == "" {
+		return nil, errors.New("torrent: missing name")
+	}
+	hash := sha1.Sum(data[d.infoStart:d.infoEnd])
+	t.InfoHash = hex.EncodeToString(hash[:])
+	t.Elements = anitogo.Parse(t.Name, options)
+
+	switch {
+	case info["files"] != nil:
+		files, ok := info["files"].([]interface{})
+		if !ok {
+			return nil, errors.New("torrent: files is not a list")
+		}
+		for i, f := range files {
+			file, err := readFileEntry(f)
+			if err != nil {
+				return nil, fmt.Errorf("torrent: file %d: %w", i, err)
+			}
+			if file.Path != "" {
+				file.Path = t.Name + "/" + file.Path
+				t.Files = append(t.Files, file)
+			}
+		}
+	case info["length"] != nil:
+		length, ok := info["length"].(int64)
+		if !ok {
+			return nil, errors.New("torrent: length is not an integer")
+		}
+		t.Files = append(t.Files, File{Path: t.Name, Size: length})
+	case info["file tree"] != nil:
+		tree, ok := info["file tree"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("torrent: file tree is not a dictionary")
+		}
+		readFileTree(tree, t.Name, &t.Files)
+	default:
+		return nil, errors.New("torrent: info dictionary has no files")
+	}
+
+	episodes := map[float64]bool{}
+	for i := range t.Files {
+		f := &t.Files[i]
+		t.Size += f.Size
+		f.Kind, f.Elements = classify(f.Path, options)
+		if f.Kind != FileKindEpisode {
+			continue
+		}
+		for _, ep := range f.Elements.EpisodeNumber {
+			if n, err := strconv.ParseFloat(ep, 64); err == nil {
+				episodes[n] = true
+			}
+		}
+		// A file holding a range, e.g "05-07", also covers the episodes between its two ends.
+		for _, report := range anitogo.AnalyzeEpisodes([]*anitogo.Elements{f.Elements}, anitogo.AnalysisOptions{}) {
+			for _, ep := range report.Episodes {
+				episodes[float64(ep)] = true
+			}
+		}
+	}
+	t.Episodes = newEpisodeRange(episodes)
+	return t, nil
+}
+
+func readFileEntry(v interface{}) (File, error) {
+	entry, ok := v.(map[string]interface{})
+	if !ok {
+		return File{}, errors.New("not a dictionary")
+	}
+	length, ok := entry["length"].(int64)
+	if !ok {
+		return File{}, errors.New("missing length")
+	}
+	parts, ok := entry["path.utf-8"].([]interface{})
+	if !ok {
+		parts, ok = entry["path"].([]interface{})
+	}
+	if !ok || len(parts) == 0 {
+		return File{}, errors.New("missing path")
+	}
+	var names []string
+	for _, p := range parts {
+		name, ok := p.(string)
+		if !ok {
+			return File{}, errors.New("path is not a list of strings")
+		}
+		names = append(names, name)
+	}
+	// Padding files (BEP 47) only align the pieces and are not part of the content.
+	if attr, _ := entry["attr"].(string); strings.Contains(attr, "p") || strings.HasPrefix(names[len(names)-1], ".pad") {
+		return File{}, nil
+	}
+	return File{Path: strings.Join(names, "/"), Size: length}, nil
+}
+
+// readFileTree appends the files of a v2 file tree in name order, since dictionaries are unordered.
+func readFileTree(tree map[string]interface{}, dir string, files *[]File) {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		node, ok := tree[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if leaf, ok := node[""].(map[string]interface{}); ok {
+			length, _ := leaf["length"].(int64)
+			*files = append(*files, File{Path: dir + "/" + name, Size: length})
+			continue
+		}
+		readFileTree(node, dir+"/"+name, files)
+	}
+}
+
+func classify(filePath string, options anitogo.Options) (FileKind, *anitogo.Elements) {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(filePath), "."))
+	dirs := strings.Split(strings.ToLower(path.Dir(filePath)), "/")
+
+	switch {
+	case containsString(fontExtensions, ext) || containsString(dirs, "fonts"):
+		return FileKindFont, nil
+	case containsString(subtitleExtensions, ext):
+		return FileKindSubtitle, anitogo.ParsePath(filePath, options)
+	case containsString(audioExtensions, ext):
+		return FileKindAudio, anitogo.ParsePath(filePath, options)
+	case !anitogo.IsVideoExtension(ext):
+		return FileKindOther, nil
+	}
+
+	e := anitogo.ParsePath(filePath, options)
+	for _, dir := range dirs {
+		if containsString(extraDirectories, dir) {
+			return FileKindExtra, e
+		}
+	}
+	for _, animeType := range e.AnimeType {
+		if containsString(extraAnimeTypes, strings.ToUpper(animeType)) {
+			return FileKindExtra, e
+		}
+	}
+	if len(e.EpisodeNumber) > 0 {
+		return FileKindEpisode, e
+	}
+	return FileKindVideo, e
+}
+
+func newEpisodeRange(episodes map[float64]bool) *EpisodeRange {
+	if len(episodes) == 0 {
+		return nil
+	}
+	r := &EpisodeRange{Count: len(episodes)}
+	first := true
+	for n := range episodes {
+		if first || n < r.First {
+			r.First = n
+		}
+		if first || n > r.Last {
+			r.Last = n
+		}
+		first = false
+	}
+	return r
+}
+
+func stringValue(dict map[string]interface{}, key string) string {
+	if s, ok := dict[key+".utf-8"].(string); ok {
+		return s
+	}
+	s, _ := dict[key].(string)
+	return s
+}
+
+func containsString(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
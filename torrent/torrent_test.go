@@ -0,0 +1,168 @@
+package torrent
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// encode bencodes the values used by the tests.
+func encode(v interface{}) string {
+	switch v := v.(type) {
+	case int:
+		return fmt.Sprintf("i%de", v)
+	case string:
+		return fmt.Sprintf("%d:%s", len(v), v)
+	case []interface{}:
+		var b strings.Builder
+		b.WriteString("l")
+		for _, item := range v {
+			b.WriteString(encode(item))
+		}
+		return b.String() + "e"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("d")
+		for _, k := range keys {
+			b.WriteString(encode(k) + encode(v[k]))
+		}
+		return b.String() + "e"
+	}
+	panic(fmt.Sprintf("cannot encode %T", v))
+}
+
+func file(length int, path ...string) map[string]interface{} {
+	parts := []interface{}{}
+	for _, p := range path {
+		parts = append(parts, p)
+	}
+	return map[string]interface{}{"length": length, "path": parts}
+}
+
+func TestTorrentRead(t *testing.T) {
+	info := map[string]interface{}{
+		"name":         "[Group] Title (BD 1080p)",
+		"piece length": 262144,
+		"pieces":       "",
+		"files": []interface{}{
+			file(100, "[Group] Title - 01 (BD 1080p) [ABCD1234].mkv"),
+			file(100, "[Group] Title - 02 (BD 1080p) [ABCD1235].mkv"),
+			file(100, "[Group] Title - 04 (BD 1080p) [ABCD1236].mkv"),
+			file(10, "Extras", "[Group] Title - NCOP (BD 1080p).mkv"),
+			file(10, "[Group] Title - NCED 01 (BD 1080p).mkv"),
+			file(5, "Subs", "[Group] Title - 01.eng.ass"),
+			file(5, "Fonts", "Font.otf"),
+			file(5, "Audio", "[Group] Title - 01.mka"),
+			file(1, "Scans", "cover.jpg"),
+			map[string]interface{}{"length": 7, "path": []interface{}{".pad", "7"}, "attr": "p"},
+		},
+	}
+	encodedInfo := encode(info)
+	data := "d" + encode("announce") + encode("http://tracker/announce") + encode("info") + encodedInfo + "e"
+
+	tor, err := Read(strings.NewReader(data), anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	hash := sha1.Sum([]byte(encodedInfo))
+	if tor.InfoHash != hex.EncodeToString(hash[:]) {
+		t.Errorf("expected info hash %x, got %s", hash, tor.InfoHash)
+	}
+	if tor.Name != "[Group] Title (BD 1080p)" || tor.Elements.AnimeTitle != "Title" || tor.Size != 336 {
+		t.Errorf("expected \"Title\" with 336 bytes, got %s, \"%s\" and %d", tor.Name, tor.Elements.AnimeTitle, tor.Size)
+	}
+
+	expected := []FileKind{
+		FileKindEpisode, FileKindEpisode, FileKindEpisode, FileKindExtra, FileKindExtra,
+		FileKindSubtitle, FileKindFont, FileKindAudio, FileKindOther,
+	}
+	if len(tor.Files) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(tor.Files))
+	}
+	for i, f := range tor.Files {
+		if f.Kind != expected[i] {
+			t.Errorf("%s: expected %s, got %s", f.Path, expected[i], f.Kind)
+		}
+	}
+	if tor.Files[0].Path != "[Group] Title (BD 1080p)/[Group] Title - 01 (BD 1080p) [ABCD1234].mkv" {
+		t.Errorf("expected the path to start with the torrent name, got %s", tor.Files[0].Path)
+	}
+	if tor.Episodes == nil || tor.Episodes.First != 1 || tor.Episodes.Last != 4 || tor.Episodes.Count != 3 {
+		t.Errorf("expected episodes 1 to 4 with 3 episodes, got %v", tor.Episodes)
+	}
+}
+
+func TestTorrentReadFile(t *testing.T) {
+	info := map[string]interface{}{"name": "[Group] Title - 05 [1080p].mkv", "length": 1024, "piece length": 262144, "pieces": ""}
+	path := filepath.Join(t.TempDir(), "single.torrent")
+	if err := os.WriteFile(path, []byte(encode(map[string]interface{}{"info": info})), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tor, err := ReadFile(path, anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(tor.Files) != 1 || tor.Files[0].Kind != FileKindEpisode || tor.Files[0].Size != 1024 {
+		t.Errorf("expected a single episode of 1024 bytes, got %v", tor.Files)
+	}
+	if tor.Episodes == nil || tor.Episodes.First != 5 || tor.Episodes.Last != 5 {
+		t.Errorf("expected episode 5, got %v", tor.Episodes)
+	}
+
+	info["name"] = "[Group] Title - 05-07 [1080p].mkv"
+	if err := os.WriteFile(path, []byte(encode(map[string]interface{}{"info": info})), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if tor, err = ReadFile(path, anitogo.DefaultOptions); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if tor.Episodes == nil || tor.Episodes.First != 5 || tor.Episodes.Last != 7 || tor.Episodes.Count != 3 {
+		t.Errorf("expected episodes 5 to 7 with 3 episodes, got %v", tor.Episodes)
+	}
+
+	tree := map[string]interface{}{
+		"Title - 02.mkv": map[string]interface{}{"": map[string]interface{}{"length": 20}},
+		"Title - 01.mkv": map[string]interface{}{"": map[string]interface{}{"length": 10}},
+	}
+	info = map[string]interface{}{"name": "Title", "meta version": 2, "file tree": tree, "piece length": 262144}
+	tor, err = Read(strings.NewReader(encode(map[string]interface{}{"info": info})), anitogo.DefaultOptions)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(tor.Files) != 2 || tor.Files[0].Path != "Title/Title - 01.mkv" || tor.Size != 30 {
+		t.Errorf("expected 2 files sorted by name, got %v", tor.Files)
+	}
+}
+
+func TestTorrentDecodeBencode(t *testing.T) {
+	invalid := []string{"", "i12", "iXe", "l", "d3:key", "5:abc", "x", "i1ei2e", "d1:ai1e", strings.Repeat("l", 100) + strings.Repeat("e", 100)}
+	for _, data := range invalid {
+		if _, _, err := decodeBencode([]byte(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+	v, _, err := decodeBencode([]byte("d4:listli1e3:twoe3:numi-5ee"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	dict := v.(map[string]interface{})
+	if dict["num"].(int64) != -5 || dict["list"].([]interface{})[1].(string) != "two" {
+		t.Errorf("unexpected value %v", v)
+	}
+	if _, err := Read(bytes.NewReader([]byte("le")), anitogo.DefaultOptions); err == nil {
+		t.Error("expected an error for a list")
+	}
+}
@@ -0,0 +1,22 @@
+package verify
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteSFV writes the results that have a checksum to w in the Simple File Verification format,
+// one "<path> <CRC32>" line per file. Paths are written as they are, so they should be relative to
+// the directory the SFV file is written to.
+func WriteSFV(w io.Writer, results []Result) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; Generated by anitogo")
+	for _, r := range results {
+		if r.Actual == "" {
+			continue
+		}
+		fmt.Fprintf(bw, "%s %s\n", r.Path, r.Actual)
+	}
+	return bw.Flush()
+}
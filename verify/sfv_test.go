@@ -0,0 +1,22 @@
+package verify
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSFVWriteSFV(t *testing.T) {
+	results := []Result{
+		{Path: "Title - 01.mkv", Status: StatusOK, Expected: "CBF43926", Actual: "CBF43926"},
+		{Path: "Title - 02.mkv", Status: StatusNoChecksum},
+		{Path: "Title - 03.mkv", Status: StatusNoChecksum, Actual: "00000001"},
+	}
+	var b bytes.Buffer
+	if err := WriteSFV(&b, results); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "; Generated by anitogo\nTitle - 01.mkv CBF43926\nTitle - 03.mkv 00000001\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
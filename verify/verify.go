@@ -0,0 +1,152 @@
+// Package verify checks files against the CRC32 checksum embedded in their name, e.g "[8F59F2BA]".
+package verify
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/nssteinbrenner/anitogo"
+)
+
+// DefaultOptions is a variable configured with the recommended defaults for the Options struct to be passed to Files.
+var DefaultOptions = Options{
+	Parser:  anitogo.DefaultOptions,
+	Workers: runtime.NumCPU(),
+}
+
+// Options configures how files are verified.
+type Options struct {
+	// DefaultOptions value: anitogo.DefaultOptions
+	// Options used to parse the name of every file.
+	Parser anitogo.Options
+
+	// DefaultOptions value: runtime.NumCPU()
+	// Number of files hashed at the same time. Values below 1 hash one file at a time.
+	Workers int
+
+	// DefaultOptions value: false
+	// If true, files without a checksum in their name are hashed too, e.g to write an SFV file.
+	HashAll bool
+}
+
+// Status is the outcome of verifying a file.
+type Status string
+
+const (
+	// StatusOK is used when the checksum of the file matches the one in its name.
+	StatusOK Status = "ok"
+
+	// StatusMismatch is used when the checksum of the file differs from the one in its name.
+	StatusMismatch Status = "mismatch"
+
+	// StatusNoChecksum is used when the name of the file has no checksum.
+	StatusNoChecksum Status = "no-checksum"
+
+	// StatusError is used when the file could not be read.
+	StatusError Status = "error"
+)
+
+// Result is the outcome of verifying a file.
+type Result struct {
+	Path   string `json:"path"`
+	Status Status `json:"status"`
+
+	// Checksum parsed from the name of the file in uppercase hex, e.g "8F59F2BA". Empty if it has none.
+	Expected string `json:"expected,omitempty"`
+
+	// Checksum of the content of the file in uppercase hex. Empty if the file was not hashed.
+	Actual string `json:"actual,omitempty"`
+
+	// Error reading the file, if Status is StatusError.
+	Err error `json:"-"`
+}
+
+// File verifies the file at path.
+func File(path string, options Options) Result {
+	r := Result{Path: path}
+	r.Expected = strings.ToUpper(anitogo.Parse(filepath.Base(path), options.Parser).FileChecksum)
+	if r.Expected == "" {
+		r.Status = StatusNoChecksum
+		if !options.HashAll {
+			return r
+		}
+	}
+
+	sum, err := CRC32File(path)
+	if err != nil {
+		r.Status, r.Err = StatusError, err
+		return r
+	}
+	r.Actual = sum
+	switch {
+	case r.Expected == "":
+	case r.Expected == r.Actual:
+		r.Status = StatusOK
+	default:
+		r.Status = StatusMismatch
+	}
+	return r
+}
+
+// Files verifies the files with options.Workers workers and returns their results in the order of paths.
+// Files not yet verified when ctx is done have StatusError and the context error.
+func Files(ctx context.Context, paths []string, options Options) []Result {
+	results := make([]Result, len(paths))
+	workers := options.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = File(paths[i], options)
+			}
+		}()
+	}
+	for i, p := range paths {
+		// select picks a ready case at random, so a cancelled context is checked first.
+		if err := ctx.Err(); err != nil {
+			results[i] = Result{Path: p, Status: StatusError, Err: err}
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i] = Result{Path: p, Status: StatusError, Err: ctx.Err()}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// CRC32 returns the IEEE CRC32 checksum of everything read from r in uppercase hex.
+func CRC32(r io.Reader) (string, error) {
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%08X", h.Sum32()), nil
+}
+
+// CRC32File returns the IEEE CRC32 checksum of the file at path in uppercase hex.
+func CRC32File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return CRC32(f)
+}
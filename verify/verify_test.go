@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestVerifyCRC32(t *testing.T) {
+	sum, err := CRC32(strings.NewReader("123456789"))
+	if err != nil || sum != "CBF43926" {
+		t.Errorf("expected CBF43926, got %s (%v)", sum, err)
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		path     string
+		hashAll  bool
+		expected Status
+		actual   string
+	}{
+		{writeFile(t, dir, "[Group] Title - 01 [CBF43926].mkv", "123456789"), false, StatusOK, "CBF43926"},
+		{writeFile(t, dir, "[Group] Title - 02 [cbf43926].mkv", "123456789"), false, StatusOK, "CBF43926"},
+		{writeFile(t, dir, "[Group] Title - 03 [00000000].mkv", "123456789"), false, StatusMismatch, "CBF43926"},
+		{writeFile(t, dir, "[Group] Title - 04.mkv", "123456789"), false, StatusNoChecksum, ""},
+		{filepath.Join(dir, "[Group] Title - 04.mkv"), true, StatusNoChecksum, "CBF43926"},
+		{filepath.Join(dir, "[Group] Title - 05 [CBF43926].mkv"), false, StatusError, ""},
+	}
+	for _, test := range tests {
+		options := DefaultOptions
+		options.HashAll = test.hashAll
+		r := File(test.path, options)
+		if r.Status != test.expected || r.Actual != test.actual {
+			t.Errorf("expected %s %q for %s, got %s %q", test.expected, test.actual, test.path, r.Status, r.Actual)
+		}
+		if (r.Status == StatusError) != (r.Err != nil) {
+			t.Errorf("expected an error only for %s, got %v", StatusError, r.Err)
+		}
+	}
+}
+
+func TestVerifyFiles(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i, name := range []string{"a [CBF43926].mkv", "b [00000000].mkv", "c.mkv", "d [CBF43926].mkv"} {
+		paths = append(paths, writeFile(t, dir, name, strings.Repeat("x", i)+"123456789"[i:]))
+	}
+	options := DefaultOptions
+	options.Workers = 3
+	results := Files(context.Background(), paths, options)
+	expected := []Status{StatusOK, StatusMismatch, StatusNoChecksum, StatusMismatch}
+	for i, r := range results {
+		if r.Path != paths[i] || r.Status != expected[i] {
+			t.Errorf("expected %s %s, got %s %s", paths[i], expected[i], r.Path, r.Status)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range Files(ctx, paths, options) {
+		if r.Status != StatusError || !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected %s to fail with %v, got %s %v", r.Path, context.Canceled, r.Status, r.Err)
+		}
+	}
+}
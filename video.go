@@ -0,0 +1,190 @@
+package anitogo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HDRFormat is a high dynamic range format the video is mastered in.
+type HDRFormat string
+
+const (
+	// HDRFormatGeneric is used when the filename only says "HDR" without naming the format.
+	HDRFormatGeneric HDRFormat = "HDR"
+
+	// HDRFormatHDR10 is used for "HDR10", with static metadata.
+	HDRFormatHDR10 HDRFormat = "HDR10"
+
+	// HDRFormatHDR10Plus is used for "HDR10+" and "HDR10Plus", with dynamic metadata.
+	HDRFormatHDR10Plus HDRFormat = "HDR10+"
+
+	// HDRFormatDolbyVision is used for "Dolby Vision", "DoVi" and "DV".
+	HDRFormatDolbyVision HDRFormat = "Dolby Vision"
+
+	// HDRFormatHLG is used for "HLG", the hybrid log-gamma format of broadcasts.
+	HDRFormatHLG HDRFormat = "HLG"
+)
+
+// Video describes the video stream of the file, built from the video terms found in the filename.
+type Video struct {
+	// Canonical name of the codec family, e.g "H.264" for "x264", "AVC" and "h264".
+	Codec string `json:"codec,omitempty"`
+
+	// Encoder used to produce the video, e.g "x264", "x265" or "NVENC".
+	Encoder string `json:"encoder,omitempty"`
+
+	// Bit depth of the video, e.g 10 for "10bit" and "Hi10P".
+	BitDepth int `json:"bit_depth,omitempty"`
+
+	// HDR formats of the video. Releases can carry several, e.g Dolby Vision with an HDR10 fallback.
+	HDR []HDRFormat `json:"hdr,omitempty"`
+
+	// Frame rate of the video in frames per second, e.g 23.976.
+	FrameRate float64 `json:"frame_rate,omitempty"`
+}
+
+type videoTermInfo struct {
+	codec    string
+	encoder  string
+	bitDepth int
+	hdr      HDRFormat
+}
+
+var videoTermTable = map[string]videoTermInfo{
+	"H264": {codec: "H.264"}, "H.264": {codec: "H.264"}, "AVC": {codec: "H.264"},
+	"X264": {codec: "H.264", encoder: "x264"}, "X.264": {codec: "H.264", encoder: "x264"},
+	"H265": {codec: "H.265"}, "H.265": {codec: "H.265"}, "HEVC": {codec: "H.265"}, "HEVC2": {codec: "H.265"},
+	"X265": {codec: "H.265", encoder: "x265"}, "X.265": {codec: "H.265", encoder: "x265"},
+	"AV1": {codec: "AV1"}, "SVT-AV1": {codec: "AV1", encoder: "SVT-AV1"},
+	"VC-1": {codec: "VC-1"}, "VC1": {codec: "VC-1"}, "WMV3": {codec: "WMV"}, "WMV9": {codec: "WMV"},
+	"VP9": {codec: "VP9"}, "MPEG2": {codec: "MPEG-2"}, "MPEG-2": {codec: "MPEG-2"},
+	"XVID": {codec: "XviD", encoder: "XviD"},
+	"DIVX": {codec: "DivX", encoder: "DivX"}, "DIVX5": {codec: "DivX", encoder: "DivX"}, "DIVX6": {codec: "DivX", encoder: "DivX"},
+	"NVENC": {encoder: "NVENC"},
+	"8BIT":  {bitDepth: 8}, "8-BIT": {bitDepth: 8},
+	"10BIT": {bitDepth: 10}, "10BITS": {bitDepth: 10}, "10-BIT": {bitDepth: 10}, "10-BITS": {bitDepth: 10},
+	"12BIT": {bitDepth: 12}, "12BITS": {bitDepth: 12}, "12-BIT": {bitDepth: 12}, "12-BITS": {bitDepth: 12},
+	"HI10": {codec: "H.264", bitDepth: 10}, "HI10P": {codec: "H.264", bitDepth: 10},
+	"HI444": {codec: "H.264"}, "HI444P": {codec: "H.264"}, "HI444PP": {codec: "H.264"},
+	"HDR": {hdr: HDRFormatGeneric}, "HDR10": {hdr: HDRFormatHDR10},
+	"HDR10+": {hdr: HDRFormatHDR10Plus}, "HDR10PLUS": {hdr: HDRFormatHDR10Plus},
+	"DV": {hdr: HDRFormatDolbyVision}, "DOVI": {hdr: HDRFormatDolbyVision}, "DOLBY VISION": {hdr: HDRFormatDolbyVision},
+	"HLG": {hdr: HDRFormatHLG},
+}
+
+var (
+	frameRatePattern     = regexp.MustCompile("(?i)^(\\d{2,3}(?:\\.\\d{1,3})?)FPS$")
+	frameRateTailPattern = regexp.MustCompile("(?i)^\\d{1,3}FPS$")
+)
+
+// searchForVideoTerms joins video terms that the tokenizer splits on delimiters, e.g "23.976FPS" and "HDR10+".
+func (p *parser) searchForVideoTerms() {
+	tkns := *p.tokenizer.tokens
+	for i, tkn := range tkns {
+		if tkn.Category == tokenCategoryDelimiter || tkn.Category == tokenCategoryBracket {
+			continue
+		}
+		normalized := p.tokenizer.keywordManager.normalize(tkn.Content)
+
+		if normalized == "HDR10" && i+1 < len(tkns) && tkns[i+1].Content == "+" {
+			if i+2 >= len(tkns) || tkns[i+2].Category != tokenCategoryUnknown {
+				p.tokenizer.elements.remove(elementCategoryVideoTerm, tkn.Content)
+				p.tokenizer.elements.insert(elementCategoryVideoTerm, tkn.Content+"+")
+				tkn.Category = tokenCategoryIdentifier
+				tkns[i+1].Category = tokenCategoryIdentifier
+			}
+			continue
+		}
+
+		if tkn.Category == tokenCategoryUnknown && isNumeric(tkn.Content) && i+2 < len(tkns) {
+			if tkns[i+1].Content == "." && frameRateTailPattern.MatchString(tkns[i+2].Content) {
+				p.tokenizer.elements.insert(elementCategoryVideoTerm, tkn.Content+"."+tkns[i+2].Content)
+				tkn.Category = tokenCategoryIdentifier
+				tkns[i+1].Category = tokenCategoryIdentifier
+				tkns[i+2].Category = tokenCategoryIdentifier
+			}
+		}
+	}
+}
+
+func (p *parser) buildVideo() {
+	e := p.tokenizer.elements
+	v := Video{}
+	found := false
+
+	for _, term := range e.VideoTerm {
+		normalized := p.tokenizer.keywordManager.normalize(term)
+		if match := frameRatePattern.FindStringSubmatch(normalized); match != nil {
+			v.FrameRate, _ = strconv.ParseFloat(match[1], 64)
+			found = true
+			continue
+		}
+		info, ok := videoTermTable[normalized]
+		if !ok {
+			continue
+		}
+		found = true
+		if info.encoder != "" && v.Encoder == "" {
+			v.Encoder = info.encoder
+		}
+		if info.codec != "" && (v.Codec == "" || info.encoder != "") {
+			v.Codec = info.codec
+		}
+		if info.bitDepth != 0 {
+			v.BitDepth = info.bitDepth
+		}
+		if info.hdr != "" {
+			v.addHDR(info.hdr)
+		}
+	}
+
+	if v.BitDepth == 0 {
+		v.BitDepth = p.findSeparatedBitDepth()
+		found = found || v.BitDepth != 0
+	}
+
+	if found {
+		e.Video = &v
+	}
+}
+
+// findSeparatedBitDepth finds bit depths written as two words, e.g "10 bit".
+func (p *parser) findSeparatedBitDepth() int {
+	tkns := *p.tokenizer.tokens
+	for i, tkn := range tkns {
+		if !isNumeric(tkn.Content) {
+			continue
+		}
+		next, found := p.tokenizer.tokens.findNext(*tkn, tokenFlagsNotDelimiter)
+		if !found || p.tokenizer.tokens.distance(tkn, next) != 2 || tkns[i+1].Content != " " {
+			continue
+		}
+		normalized := strings.ToUpper(next.Content)
+		if normalized != "BIT" && normalized != "BITS" {
+			continue
+		}
+		depth, _ := strconv.Atoi(tkn.Content)
+		if depth == 8 || depth == 10 || depth == 12 {
+			return depth
+		}
+	}
+	return 0
+}
+
+func (v *Video) addHDR(format HDRFormat) {
+	for i, f := range v.HDR {
+		if f == format {
+			return
+		}
+		// A named format is more specific than a plain "HDR" tag.
+		if f == HDRFormatGeneric {
+			v.HDR[i] = format
+			return
+		}
+	}
+	if format == HDRFormatGeneric && len(v.HDR) > 0 {
+		return
+	}
+	v.HDR = append(v.HDR, format)
+}
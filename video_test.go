@@ -0,0 +1,56 @@
+package anitogo
+
+import (
+	"testing"
+)
+
+func TestVideoBuildVideo(t *testing.T) {
+	e := Parse("[Group] Title - 01 [1080p HEVC x265 10bit HDR10+ DV 23.976fps].mkv", DefaultOptions)
+	if e.Video == nil {
+		t.Fatal("expected video, got nil")
+	}
+	if e.Video.Codec != "H.265" {
+		t.Errorf("expected \"H.265\", got \"%s\"", e.Video.Codec)
+	}
+	if e.Video.Encoder != "x265" {
+		t.Errorf("expected \"x265\", got \"%s\"", e.Video.Encoder)
+	}
+	if e.Video.BitDepth != 10 {
+		t.Errorf("expected 10, got %d", e.Video.BitDepth)
+	}
+	if len(e.Video.HDR) != 2 || e.Video.HDR[0] != HDRFormatHDR10Plus || e.Video.HDR[1] != HDRFormatDolbyVision {
+		t.Errorf("expected [HDR10+ Dolby Vision], got %v", e.Video.HDR)
+	}
+	if e.Video.FrameRate != 23.976 {
+		t.Errorf("expected 23.976, got %f", e.Video.FrameRate)
+	}
+	if e.EpisodeNumber[0] != "01" {
+		t.Errorf("expected \"01\", got \"%s\"", e.EpisodeNumber[0])
+	}
+
+	e = Parse("[TaigaSubs]_Toradora!_(2008)_-_01v2_-_Tiger_and_Dragon_[1280x720_Hi10P_FLAC][1234ABCD].mkv", DefaultOptions)
+	if e.Video == nil || e.Video.Codec != "H.264" || e.Video.BitDepth != 10 || e.Video.Encoder != "" {
+		t.Errorf("expected H.264 with a bit depth of 10, got %v", e.Video)
+	}
+
+	e = Parse("[Nubles] Space Battleship Yamato 2199 (2012) episode 18 (720p 10 bit AAC)[1F56D642]", DefaultOptions)
+	if e.Video == nil || e.Video.BitDepth != 10 {
+		t.Errorf("expected a bit depth of 10, got %v", e.Video)
+	}
+
+	e = Parse("[Group] Title - 01 [720p].mkv", DefaultOptions)
+	if e.Video != nil {
+		t.Errorf("expected nil, got %v", e.Video)
+	}
+}
+
+func TestVideoAddHDR(t *testing.T) {
+	v := Video{}
+	v.addHDR(HDRFormatGeneric)
+	v.addHDR(HDRFormatHDR10)
+	v.addHDR(HDRFormatGeneric)
+	v.addHDR(HDRFormatHLG)
+	if len(v.HDR) != 2 || v.HDR[0] != HDRFormatHDR10 || v.HDR[1] != HDRFormatHLG {
+		t.Errorf("expected [HDR10 HLG], got %v", v.HDR)
+	}
+}